@@ -0,0 +1,96 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/lint"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/splitter"
+)
+
+// runLint runs the lint subcommand and returns the exit code: 1 when a file
+// has a finding of error severity or cannot be checked, 0 otherwise
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	var (
+		configFile    string
+		outputFormat  string
+		oracleVersion string
+		listRules     bool
+	)
+	flags.StringVar(&configFile, "config", "", "JSON file enabling, disabling and setting the severity of rules")
+	flags.StringVar(&outputFormat, "format", "text", "Output format: text or json")
+	flags.StringVar(&oracleVersion, "oracle-version", "", "Oracle release whose syntax is accepted: 10g, 11g, 12c, 18c, 19c, 21c or 23ai (default latest)")
+	flags.BoolVar(&listRules, "list-rules", false, "List the rules with their severities and exit")
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage:")
+		fmt.Fprintln(flags.Output(), "  splitter lint [options] <file>...")
+		fmt.Fprintln(flags.Output(), "\nOptions:")
+		flags.PrintDefaults()
+	}
+	flags.Parse(args)
+
+	var options []lint.Option
+	if configFile != "" {
+		config, err := lint.LoadConfig(configFile)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		options = append(options, lint.WithConfig(config))
+	}
+	if oracleVersion != "" {
+		version, err := splitter.ParseOracleVersion(oracleVersion)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		options = append(options, lint.WithSplitterOptions(splitter.WithOracleVersion(version)))
+	}
+	linter := lint.NewLinter(options...)
+
+	if listRules {
+		for _, rule := range linter.Rules() {
+			fmt.Printf("%-26s %-8s %s\n", rule.Name(), linter.Severity(rule), rule.Description())
+		}
+		return 0
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return 1
+	}
+
+	exitCode := 0
+	findings := []lint.Finding{}
+	for _, path := range flags.Args() {
+		fileFindings, err := linter.LintFile(path)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			exitCode = 1
+			continue
+		}
+		for _, finding := range fileFindings {
+			if finding.Severity == lint.SeverityError {
+				exitCode = 1
+			}
+		}
+		findings = append(findings, fileFindings...)
+	}
+
+	if outputFormat == "json" {
+		data, err := json.MarshalIndent(findings, "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error marshalling to JSON: %v\n", err)
+			return 1
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, finding := range findings {
+			fmt.Println(finding)
+		}
+	}
+	return exitCode
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -12,6 +13,10 @@ import (
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+
 	// Define flags
 	var (
 		outputFormat        string
@@ -27,6 +32,17 @@ func main() {
 		jsonPretty          bool
 		jsonIndent          string
 		contextLines        int
+		cacheDir            string
+		oracleVersion       string
+		ccFlags             string
+		sqlTerminator       string
+		blockTerminator     string
+		sqlBlankLines       bool
+		showWarnings        bool
+		warningsAsErrors    string
+		blockChecks         bool
+		checkIdentifiers    bool
+		compatibility       string
 	)
 
 	flag.StringVar(&outputFormat, "format", "text", "Output format: text or json")
@@ -42,6 +58,17 @@ func main() {
 	flag.BoolVar(&jsonPretty, "pretty", true, "Pretty print JSON output")
 	flag.StringVar(&jsonIndent, "indent", "  ", "Indentation for JSON output")
 	flag.IntVar(&contextLines, "context-lines", 3, "Number of context lines to show before and after errors")
+	flag.StringVar(&cacheDir, "cache-dir", "", "Directory for caching results of unchanged files")
+	flag.StringVar(&oracleVersion, "oracle-version", "", "Oracle release whose syntax is accepted: 10g, 11g, 12c, 18c, 19c, 21c or 23ai (default latest)")
+	flag.StringVar(&ccFlags, "ccflags", "", "Evaluate conditional compilation with PLSQL_CCFLAGS-style values, such as debug:TRUE,level:2")
+	flag.StringVar(&sqlTerminator, "sqlterminator", "", "SQL*Plus SQLTERMINATOR setting at the start of the script: ON, OFF or a symbol")
+	flag.StringVar(&blockTerminator, "blockterminator", "", "SQL*Plus BLOCKTERMINATOR setting at the start of the script: ON, OFF or a symbol")
+	flag.BoolVar(&sqlBlankLines, "sqlblanklines", true, "Allow blank lines inside SQL statements, like SET SQLBLANKLINES ON")
+	flag.BoolVar(&showWarnings, "warnings", false, "Print warnings about statements that are likely to misbehave in SQL*Plus")
+	flag.StringVar(&warningsAsErrors, "warnings-as-errors", "", "Reject the script on the warnings with these comma-separated codes, or on any warning with all")
+	flag.BoolVar(&blockChecks, "block-checks", false, "Report END names and labels that do not match, RAISE outside handlers and unreachable RETURNs as errors")
+	flag.BoolVar(&checkIdentifiers, "check-identifiers", false, "Print warnings about reserved words used as names, identifiers too long for -oracle-version and quoted names differing only by case")
+	flag.StringVar(&compatibility, "compatibility", "", "Print the syntax that this Oracle release does not support, with the release that introduced it")
 	flag.Parse()
 
 	// Check if a file path was provided
@@ -49,6 +76,7 @@ func main() {
 	if len(args) == 0 {
 		fmt.Println("Usage:")
 		fmt.Println("  splitter [options] <file>")
+		fmt.Println("  splitter lint [options] <file>...")
 		fmt.Println("  If no file is provided, a demo will be run")
 		fmt.Println("\nOptions:")
 		flag.PrintDefaults()
@@ -58,6 +86,15 @@ func main() {
 		fmt.Println("  splitter -format=json -output=result.json script.sql")
 		fmt.Println("  splitter -verbose-errors script.sql")
 		fmt.Println("  splitter -all-errors -error-context -context-lines=5 invalid.sql")
+		fmt.Println("  splitter -cache-dir=.splitter-cache script.sql")
+		fmt.Println("  splitter -oracle-version=11g script.sql")
+		fmt.Println("  splitter -ccflags=debug:TRUE,level:2 package.sql")
+		fmt.Println("  splitter -sqlterminator=# -sqlblanklines=false legacy.sql")
+		fmt.Println("  splitter -warnings -warnings-as-errors=PLS-SPLIT-1001 deploy.sql")
+		fmt.Println("  splitter -block-checks package_body.sql")
+		fmt.Println("  splitter -check-identifiers -oracle-version=12c schema.sql")
+		fmt.Println("  splitter -compatibility=11g reports.sql")
+		fmt.Println("  splitter lint -config=lint.json deploy/*.sql")
 
 		fmt.Println("\nRunning demo...")
 		demoSplitString()
@@ -84,6 +121,60 @@ func main() {
 	if includeErrorStmt {
 		splitterOpts = append(splitterOpts, splitter.WithErrorStatement(true))
 	}
+	if cacheDir != "" {
+		splitterOpts = append(splitterOpts, splitter.WithCache(cacheDir))
+	}
+	if oracleVersion != "" {
+		version, err := splitter.ParseOracleVersion(oracleVersion)
+		if err != nil {
+			log.Fatalf("Invalid -oracle-version: %v", err)
+		}
+		splitterOpts = append(splitterOpts, splitter.WithOracleVersion(version))
+	}
+	var target splitter.OracleVersion
+	if compatibility != "" {
+		version, err := splitter.ParseOracleVersion(compatibility)
+		if err != nil {
+			log.Fatalf("Invalid -compatibility: %v", err)
+		}
+		target = version
+	}
+	flag.Visit(func(f *flag.Flag) {
+		// An empty -ccflags still evaluates the directives, with every flag NULL
+		if f.Name != "ccflags" {
+			return
+		}
+		flags, err := splitter.ParseCCFlags(ccFlags)
+		if err != nil {
+			log.Fatalf("Invalid -ccflags: %v", err)
+		}
+		splitterOpts = append(splitterOpts, splitter.WithCCFlags(flags))
+	})
+	if sqlTerminator != "" {
+		c, err := splitter.ParseTerminator(sqlTerminator)
+		if err != nil {
+			log.Fatalf("Invalid -sqlterminator: %v", err)
+		}
+		splitterOpts = append(splitterOpts, splitter.WithSQLTerminator(c))
+	}
+	if blockTerminator != "" {
+		c, err := splitter.ParseTerminator(blockTerminator)
+		if err != nil {
+			log.Fatalf("Invalid -blockterminator: %v", err)
+		}
+		splitterOpts = append(splitterOpts, splitter.WithBlockTerminator(c))
+	}
+	if !sqlBlankLines {
+		splitterOpts = append(splitterOpts, splitter.WithSQLBlankLines(false))
+	}
+	if blockChecks {
+		splitterOpts = append(splitterOpts, splitter.WithBlockChecks(true))
+	}
+	if warningsAsErrors == "all" {
+		splitterOpts = append(splitterOpts, splitter.WithWarningsAsErrors())
+	} else if warningsAsErrors != "" {
+		splitterOpts = append(splitterOpts, splitter.WithWarningsAsErrors(strings.Split(warningsAsErrors, ",")...))
+	}
 
 	s := splitter.NewSplitter(splitterOpts...)
 
@@ -97,12 +188,38 @@ func main() {
 	}
 
 	statements, err := s.SplitFile(filePath)
+
+	// Warnings go to stderr so that they do not mix with the output
+	if showWarnings {
+		if data, readErr := os.ReadFile(filePath); readErr == nil {
+			for _, d := range s.Warnings(string(data)) {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filePath, d.Error())
+			}
+		}
+	}
+	if compatibility != "" {
+		if data, readErr := os.ReadFile(filePath); readErr == nil {
+			diagnostics, _ := s.CompatibilityReport(string(data), target)
+			for _, d := range diagnostics {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filePath, d.Error())
+			}
+		}
+	}
+	if checkIdentifiers && err == nil {
+		if data, readErr := os.ReadFile(filePath); readErr == nil {
+			diagnostics, _ := s.CheckIdentifiers(string(data))
+			for _, d := range diagnostics {
+				fmt.Fprintf(os.Stderr, "%s: %s\n", filePath, d.Error())
+			}
+		}
+	}
+
 	if err != nil {
-		syntaxErr, isSyntaxErr := err.(*splitter.SyntaxError)
-		if isSyntaxErr {
+		var syntaxErr *splitter.SyntaxError
+		if errors.As(err, &syntaxErr) {
 			if verboseErrors || includeErrorContext {
-				// Print the full error message with context if available
-				log.Fatalf("Syntax error:\n%s", syntaxErr.Error())
+				// Print every error with its context if available
+				log.Fatalf("Syntax error:\n%s", err.Error())
 			} else {
 				log.Fatalf("Syntax error at line %d, column %d: %s",
 					syntaxErr.Line, syntaxErr.Column, syntaxErr.Message)
@@ -245,9 +362,8 @@ INSERT INTO employees (id, name) VALUES (1, 'John);
 	_, err := s.SplitString(script)
 	if err != nil {
 		// Check if it's a syntax error
-		syntaxErr, isSyntaxErr := err.(*splitter.SyntaxError)
-		if isSyntaxErr {
-			fmt.Printf("Syntax error detected:\n%s\n", syntaxErr.Error())
+		if errors.Is(err, splitter.ErrSyntax) {
+			fmt.Printf("Syntax error detected:\n%s\n", err.Error())
 		} else {
 			fmt.Printf("Error: %v\n", err)
 		}
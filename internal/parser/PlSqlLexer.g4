@@ -29,6 +29,12 @@ options {
 #include <PlSqlLexerBase.h>
 }
 
+// The payload of a wrapped unit or of a Java source up to its slash line,
+// which PlSqlLexerBase returns as one token instead of lexing it
+tokens {
+    OPAQUE_BODY
+}
+
 ABORT:                        'ABORT';
 ABS:                          'ABS';
 ABSENT:                       'ABSENT';
@@ -2307,6 +2313,15 @@ ROOT:                         'ROOT';
 UNITE:                        'UNITE';
 ALGORITHM:                    'ALGORITHM';
 
+ANNOTATIONS:                  'ANNOTATIONS';
+DISPLAY:                      'DISPLAY';
+DOMAIN:                       'DOMAIN';
+DUALITY:                      'DUALITY';
+ETAG:                         'ETAG';
+NODELETE:                     'NODELETE';
+NOINSERT:                     'NOINSERT';
+NOUPDATE:                     'NOUPDATE';
+
 CUME_DIST:                    'CUME_DIST';
 DENSE_RANK:                   'DENSE_RANK';
 LISTAGG:                      'LISTAGG';
@@ -2528,6 +2543,18 @@ HASH_OP:                   '#';
 
 SQ:                        '\'';
 
+// Conditional compilation directives, which the parser never sees: the
+// directive filter hides them together with the code of branches that are not
+// selected
+CC_IF:     '$IF';
+CC_THEN:   '$THEN';
+CC_ELSIF:  '$ELSIF';
+CC_ELSE:   '$ELSE';
+CC_END:    '$END';
+CC_ERROR:  '$ERROR';
+
+INQUIRY_DIRECTIVE: '$$' SIMPLE_LETTER (SIMPLE_LETTER | [0-9] | '_' | '$' | '#')*;
+
 BINDVAR
     : ':' SIMPLE_LETTER  (SIMPLE_LETTER | [0-9] | '_')*
     | ':' DELIMITED_ID  // not used in SQL but spotted in v$sqltext when using cursor_sharing
@@ -2553,6 +2580,8 @@ EQUALS_OP: '=';
 
 LEFT_BRACKET:  '[';
 RIGHT_BRACKET: ']';
+LEFT_CURLY_BRACKET:  '{';
+RIGHT_CURLY_BRACKET: '}';
 
 INTRODUCER: '_';
 
@@ -86,6 +86,7 @@ unit_statement
     | create_dimension
     | create_directory
     | create_diskgroup
+    | create_domain
     | create_edition
     | create_flashback_archive
     | create_function_body
@@ -93,6 +94,7 @@ unit_statement
     | create_index
     | create_inmemory_join_group
     | create_java
+    | create_json_duality_view
     | create_library
     | create_lockdown_profile
     | create_materialized_view
@@ -118,6 +120,7 @@ unit_statement
     | create_type
     | create_user
     | create_view
+    | create_wrapped_unit
 
     | drop_analytic_view
     | drop_attribute_dimension
@@ -128,6 +131,7 @@ unit_statement
     | drop_database_link
     | drop_directory
     | drop_diskgroup
+    | drop_domain
     | drop_edition
     | drop_flashback_archive
     | drop_function
@@ -448,7 +452,7 @@ enable_disable_volume
 // Function DDLs
 
 drop_function
-    : DROP FUNCTION function_name ';'
+    : DROP FUNCTION if_exists? function_name ';'
     ;
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/ALTER-FLASHBACK-ARCHIVE.html
@@ -474,6 +478,24 @@ editionable_noneditionable
     : {p.isVersion12()}? (EDITIONABLE | NONEDITIONABLE)
     ;
 
+// IF EXISTS and IF NOT EXISTS were added in 23ai
+if_exists
+    : {p.isVersion23()}? IF EXISTS
+    ;
+
+if_not_exists
+    : {p.isVersion23()}? IF NOT EXISTS
+    ;
+
+// https://docs.oracle.com/en/database/oracle/oracle-database/23/sqlrf/annotations_clause.html
+annotations_clause
+    : {p.isVersion23()}? ANNOTATIONS '(' annotation (',' annotation)* ')'
+    ;
+
+annotation
+    : (ADD if_not_exists? | DROP if_exists? | REPLACE)? id_expression quoted_string?
+    ;
+
 alter_function
     : ALTER FUNCTION function_name ((COMPILE DEBUG? compiler_parameters_clause* (REUSE SETTINGS)?)|editionable_noneditionable?) ';'
     ;
@@ -600,7 +622,7 @@ drop_lockdown_profile
 // Package DDLs
 
 drop_package
-    : DROP PACKAGE BODY? (schema_object_name '.')? package_name ';'
+    : DROP PACKAGE BODY? if_exists? (schema_object_name '.')? package_name ';'
     ;
 
 alter_package
@@ -668,7 +690,7 @@ drop_pmem_filestore
 // Procedure DDLs
 
 drop_procedure
-    : DROP PROCEDURE procedure_name ';'
+    : DROP PROCEDURE if_exists? procedure_name ';'
     ;
 
 alter_procedure
@@ -746,7 +768,7 @@ create_rollback_segment
 // Trigger DDLs
 
 drop_trigger
-    : DROP TRIGGER trigger_name ';'
+    : DROP TRIGGER if_exists? trigger_name ';'
     ;
 
 alter_trigger
@@ -857,7 +879,7 @@ referencing_element
 // DDLs
 
 drop_type
-    : DROP TYPE BODY? type_name (FORCE | VALIDATE)? ';'
+    : DROP TYPE BODY? if_exists? type_name (FORCE | VALIDATE)? ';'
     ;
 
 alter_type
@@ -1052,7 +1074,7 @@ type_elements_parameter
 // Sequence DDLs
 
 drop_sequence
-    : DROP SEQUENCE sequence_name ';'
+    : DROP SEQUENCE if_exists? sequence_name ';'
     ;
 
 alter_sequence
@@ -1080,7 +1102,24 @@ alter_session_set_clause
     ;
 
 create_sequence
-    : CREATE SEQUENCE sequence_name (sequence_start_clause | sequence_spec)* ';'
+    : CREATE SEQUENCE if_not_exists? sequence_name (sequence_start_clause | sequence_spec)* ';'
+    ;
+
+// https://docs.oracle.com/en/database/oracle/oracle-database/23/sqlrf/create-domain.html
+create_domain
+    : {p.isVersion23()}? CREATE (OR REPLACE)? DOMAIN if_not_exists? (schema_name '.')? domain_name
+      AS datatype STRICT? (COLLATE column_collation_name)?
+      (DEFAULT (ON NULL_)? expression)?
+      inline_constraint*
+      (DISPLAY expression)?
+      (ORDER expression)?
+      annotations_clause?
+      ';'
+    ;
+
+// https://docs.oracle.com/en/database/oracle/oracle-database/23/sqlrf/drop-domain.html
+drop_domain
+    : {p.isVersion23()}? DROP DOMAIN if_exists? (schema_name '.')? domain_name (FORCE PRESERVE?)? ';'
     ;
 
 // Common Sequence
@@ -1470,7 +1509,7 @@ hier_attr_name
     ;
 
 create_index
-    : CREATE (UNIQUE | BITMAP)? INDEX index_name
+    : CREATE (UNIQUE | BITMAP)? INDEX if_not_exists? index_name
        ON (cluster_index_clause | table_index_clause | bitmap_join_index_clause)
        (USABLE | UNUSABLE)?
        ';'
@@ -2367,7 +2406,7 @@ sql_statement_shortcut
     ;
 
 drop_index
-    : DROP INDEX index_name ';'
+    : DROP INDEX if_exists? index_name ';'
     ;
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/DISASSOCIATE-STATISTICS.html
@@ -2547,9 +2586,18 @@ create_java
         (RESOLVER '(' ('(' CHAR_STRING ','? (sn=id_expression | '-') ')')+ ')')?
         ( USING (BFILE '(' d=id_expression ',' filename ')' | (CLOB | BLOB | BFILE) subquery | CHAR_STRING)
         | AS CHAR_STRING
+        | AS OPAQUE_BODY
         )
     ;
 
+// Units obfuscated with the wrap utility, whose payload the lexer returns as
+// one OPAQUE_BODY token
+create_wrapped_unit
+    : CREATE (OR REPLACE)? editionable_noneditionable?
+        (PACKAGE BODY? | PROCEDURE | FUNCTION | TYPE BODY?) (schema_object_name '.')? identifier
+        WRAPPED OPAQUE_BODY
+    ;
+
 create_library
     : CREATE (OR REPLACE)? editionable_noneditionable? LIBRARY plsql_library_source
     ;
@@ -2642,9 +2690,10 @@ alter_view_editionable
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/CREATE-VIEW.html
 create_view
-    : CREATE (OR REPLACE)? (NO? FORCE)? editioning_clause? VIEW (schema_name '.')? v=id_expression
+    : CREATE (OR REPLACE)? (NO? FORCE)? editioning_clause? VIEW if_not_exists? (schema_name '.')? v=id_expression
       (SHARING '=' (METADATA | EXTENDED? DATA | NONE))?
       view_options?
+      annotations_clause?
       (DEFAULT COLLATION cn=id_expression)?
       (BEQUEATH (CURRENT_USER | DEFINER))?
       AS select_only_statement subquery_restriction_clause?
@@ -2723,6 +2772,38 @@ xmltype_view_clause
     : OF XMLTYPE xml_schema_spec? WITH OBJECT (IDENTIFIER | ID) (DEFAULT | '(' expression (',' expression)* ')')
     ;
 
+// https://docs.oracle.com/en/database/oracle/oracle-database/23/sqlrf/create-json-relational-duality-view.html
+create_json_duality_view
+    : {p.isVersion23()}? CREATE (OR REPLACE)? (NO? FORCE)? editioning_clause? JSON RELATIONAL? DUALITY VIEW if_not_exists?
+      (schema_name '.')? v=id_expression
+      annotations_clause?
+      AS duality_view_subquery
+    ;
+
+duality_view_subquery
+    : SELECT duality_view_object FROM tableview_name table_alias? duality_view_tags? where_clause?
+    ;
+
+duality_view_object
+    : JSON '{' duality_view_field (',' duality_view_field)* '}'
+    | JSON_OBJECT '(' duality_view_field (',' duality_view_field)* ')'
+    ;
+
+duality_view_field
+    : KEY? (quoted_string | identifier) (':' | VALUE | IS) duality_view_value
+    ;
+
+duality_view_value
+    : '(' duality_view_subquery ')'
+    | '[' duality_view_subquery ']'
+    | UNNEST '(' duality_view_subquery ')'
+    | expression duality_view_tags?
+    ;
+
+duality_view_tags
+    : WITH (INSERT | UPDATE | DELETE | NOINSERT | NOUPDATE | NODELETE | CHECK | NOCHECK | ETAG)+
+    ;
+
 xml_schema_spec
     : (XMLSCHEMA xml_schema_url)? ELEMENT (element | xml_schema_url '#' element)
         (STORE ALL VARRAYS AS (LOBS | TABLES))?
@@ -3330,9 +3411,10 @@ create_table
             | IMMUTABLE? BLOCKCHAIN
             | IMMUTABLE
             )?
-        TABLE (schema_name '.')? table_name
+        TABLE if_not_exists? (schema_name '.')? table_name
         (SHARING '=' (METADATA | EXTENDED? DATA | NONE))?
         (relational_table | object_table | xmltype_table)
+        annotations_clause?
         (MEMOPTIMIZE FOR READ)?
         (MEMOPTIMIZE FOR WRITE)?
         (PARENT tableview_name)?
@@ -4181,7 +4263,7 @@ truncate_table
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/DROP-TABLE.html
 drop_table
-    : DROP TABLE tableview_name (CASCADE CONSTRAINTS)? PURGE? SEMICOLON
+    : DROP TABLE if_exists? tableview_name (CASCADE CONSTRAINTS)? PURGE? SEMICOLON
     ;
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/DROP-TABLESPACE.html
@@ -4201,7 +4283,7 @@ including_contents_clause
     ;
 
 drop_view
-    : DROP VIEW tableview_name (CASCADE CONSTRAINT)? SEMICOLON
+    : DROP VIEW if_exists? tableview_name (CASCADE CONSTRAINT)? SEMICOLON
     ;
 
 comment_on_column
@@ -4230,7 +4312,7 @@ create_synonym
     ;
 
 drop_synonym
-    : DROP PUBLIC? SYNONYM (schema_name '.')? synonym_name FORCE?
+    : DROP PUBLIC? SYNONYM if_exists? (schema_name '.')? synonym_name FORCE?
     ;
 
 // https://docs.oracle.com/en/database/oracle/oracle-database/21/sqlrf/CREATE-SPFILE.html
@@ -5292,11 +5374,17 @@ end_time_column
 column_definition
     : column_name
          ( (datatype | regular_id) (COLLATE column_collation_name)?)?
+         column_domain_clause?
          SORT?
          (VISIBLE | INVISIBLE)?
          (DEFAULT (ON NULL_)? expression | identity_clause)?
          (ENCRYPT encryption_spec)?
          (inline_constraint+ | inline_ref_constraint)?
+         annotations_clause?
+    ;
+
+column_domain_clause
+    : {p.isVersion23()}? DOMAIN (schema_name '.')? domain_name
     ;
 
 column_collation_name
@@ -5860,7 +5948,13 @@ select_statement
 // Select Specific Clauses
 
 subquery_factoring_clause
-    : WITH factoring_element (',' factoring_element)*
+    : WITH with_plsql_declaration+ (factoring_element (',' factoring_element)*)?
+    | WITH factoring_element (',' factoring_element)*
+    ;
+
+// PL/SQL functions and procedures declared in a WITH clause were added in 12c
+with_plsql_declaration
+    : {p.isVersion12()}? (function_body | procedure_body)
     ;
 
 factoring_element
@@ -5892,7 +5986,7 @@ subquery_operation_part
 
 query_block
     : subquery_factoring_clause? SELECT (DISTINCT | UNIQUE | ALL)? selected_list
-      into_clause? from_clause where_clause? hierarchical_query_clause? group_by_clause? model_clause? order_by_clause? fetch_clause?
+      into_clause? (from_clause | no_from_clause) where_clause? hierarchical_query_clause? group_by_clause? model_clause? order_by_clause? fetch_clause?
     ;
 
 selected_list
@@ -5904,6 +5998,11 @@ from_clause
     : FROM table_ref_list
     ;
 
+// Oracle 23ai makes the FROM clause optional
+no_from_clause
+    : {p.isVersion23()}?
+    ;
+
 select_list_elements
     : table_wild
     | expression column_alias?
@@ -5934,6 +6033,7 @@ table_ref_aux_internal
     : dml_table_expression_clause (pivot_clause | unpivot_clause)?                 # table_ref_aux_internal_one
     | '(' table_ref subquery_operation_part* ')' (pivot_clause | unpivot_clause)?  # table_ref_aux_internal_two
     | ONLY '(' dml_table_expression_clause ')'                                     # table_ref_aux_internal_three
+    | {p.isVersion23()}? '(' table_value_constructor ')' table_alias paren_column_list # table_ref_aux_internal_values
     ;
 
 join_clause
@@ -6186,7 +6286,16 @@ insert_into_clause
     ;
 
 values_clause
-    : VALUES (expression | '(' expressions ')')
+    : VALUES (expression | '(' expressions ')') table_value_rows?
+    ;
+
+// https://docs.oracle.com/en/database/oracle/oracle-database/23/sqlrf/SELECT.html
+table_value_constructor
+    : VALUES '(' expressions ')' table_value_rows?
+    ;
+
+table_value_rows
+    : {p.isVersion23()}? (',' '(' expressions ')')+
     ;
 
 merge_statement
@@ -6924,6 +7033,10 @@ sequence_name
     : id_expression ('.' id_expression)*
     ;
 
+domain_name
+    : id_expression
+    ;
+
 exception_name
     : identifier ('.' id_expression)*
     ;
@@ -7451,6 +7564,7 @@ non_reserved_keywords_in_12c
     | ALGORITHM
     | ANALYTIC
     | ANCESTOR
+    | ANNOTATIONS
     | ANOMALY
     | ANSI_REARCH
     | APPLICATION
@@ -7530,7 +7644,10 @@ non_reserved_keywords_in_12c
     | DISABLE_ALL
     | DISABLE_PARALLEL_DML
     | DISCARD
+    | DISPLAY
     | DISTRIBUTE
+    | DOMAIN
+    | DUALITY
     | DUPLICATE
     | DUPLICATED
     | DV
@@ -7540,6 +7657,7 @@ non_reserved_keywords_in_12c
     | ENABLE_ALL
     | ENABLE_PARALLEL_DML
     | EQUIPART
+    | ETAG
     | EVAL
     | EVALUATE
     | EXISTING
@@ -7634,11 +7752,14 @@ non_reserved_keywords_in_12c
     | MULTIDIMENSIONAL
     | NEG
     | NOCOPY
+    | NODELETE
+    | NOINSERT
     | NOKEEP
     | NONEDITIONABLE
     | NOPARTITION
     | NORELOCATE
     | NOREPLAY
+    | NOUPDATE
     | NO_ADAPTIVE_PLAN
     | NO_ANSI_REARCH
     | NO_AUTO_REOPTIMIZE
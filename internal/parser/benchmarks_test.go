@@ -0,0 +1,87 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/test/samples"
+)
+
+// largeScriptSize is the size of the generated benchmark scripts, about 4 MB
+const largeScriptSize = 4 << 20
+
+func benchmarkParse(b *testing.B, opts ParseOptions) {
+	script := samples.GenerateLargeScript(largeScriptSize)
+	b.SetBytes(int64(len(script)))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, syntaxErrors, err := Parse(script, opts); err != nil || len(syntaxErrors) > 0 {
+			b.Fatalf("Parse failed: %v %v", err, syntaxErrors)
+		}
+	}
+}
+
+// BenchmarkParse_TwoStage parses with SLL first, falling back to LL on failure
+func BenchmarkParse_TwoStage(b *testing.B) {
+	benchmarkParse(b, ParseOptions{MaxErrors: 1})
+}
+
+// BenchmarkParse_LLOnly parses with full LL prediction only
+func BenchmarkParse_LLOnly(b *testing.B) {
+	benchmarkParse(b, ParseOptions{MaxErrors: 1, LLOnly: true})
+}
+
+// BenchmarkParse_Lexical splits using the token stream only
+func BenchmarkParse_Lexical(b *testing.B) {
+	benchmarkParse(b, ParseOptions{Mode: ModeLexical})
+}
+
+// smallScript is typical of the scripts sent to a validation service
+const smallScript = `UPDATE employees SET salary = salary * 1.1 WHERE dept_id = 10;
+BEGIN
+  audit_pkg.log('raise applied');
+END;
+/`
+
+func benchmarkSmallScripts(b *testing.B, pooled bool) {
+	previous := poolParsers
+	poolParsers = pooled
+	defer func() { poolParsers = previous }()
+
+	WarmUp()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, _, err := ParseString(smallScript); err != nil {
+			b.Fatalf("Parse failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkParse_SmallPooled parses small scripts with pooled lexers and parsers
+func BenchmarkParse_SmallPooled(b *testing.B) {
+	benchmarkSmallScripts(b, true)
+}
+
+// BenchmarkParse_SmallUnpooled parses small scripts with fresh lexers and parsers
+func BenchmarkParse_SmallUnpooled(b *testing.B) {
+	benchmarkSmallScripts(b, false)
+}
+
+// BenchmarkParse_SmallParallel parses small scripts from many goroutines
+func BenchmarkParse_SmallParallel(b *testing.B) {
+	WarmUp()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, _, err := ParseString(smallScript); err != nil {
+				b.Errorf("Parse failed: %v", err)
+				return
+			}
+		}
+	})
+}
@@ -0,0 +1,425 @@
+package parser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+)
+
+// Codes of the errors found by the block structure checks. The grammar
+// accepts such code, but the Oracle compiler rejects it or it fails at run
+// time.
+const (
+	CodeEndLabelMismatch    = "PLS-SPLIT-0011" // The name after END is not the name of the unit or the label of the block
+	CodeLoopLabelMismatch   = "PLS-SPLIT-0012" // The label after END LOOP is not the label of the loop
+	CodeRaiseOutsideHandler = "PLS-SPLIT-0013" // RAISE without an exception is outside an exception handler
+	CodeMissingReturn       = "PLS-SPLIT-0014" // A function can reach its END without a RETURN
+	CodeUnreachableReturn   = "PLS-SPLIT-0015" // A RETURN follows a statement that never completes
+)
+
+// blockChecker reports block structure errors during the walk: END names and
+// labels that do not match what they close, RAISE statements that re-raise
+// outside a handler, and functions whose RETURN cannot be reached
+type blockChecker struct {
+	*gen.BasePlSqlParserListener
+	errors *CustomErrorListener
+}
+
+// newBlockChecker creates a block checker reporting to errorListener
+func newBlockChecker(errorListener *CustomErrorListener) *blockChecker {
+	return &blockChecker{
+		BasePlSqlParserListener: &gen.BasePlSqlParserListener{},
+		errors:                  errorListener,
+	}
+}
+
+// EnterCreate_procedure_body checks the name after the END of a procedure
+func (c *blockChecker) EnterCreate_procedure_body(ctx *gen.Create_procedure_bodyContext) {
+	c.checkUnitEnd(ctx, "PROCEDURE", lastName(childRule(ctx, gen.PlSqlParserRULE_procedure_name)))
+}
+
+// EnterCreate_function_body checks the name after the END of a function and
+// that it returns
+func (c *blockChecker) EnterCreate_function_body(ctx *gen.Create_function_bodyContext) {
+	name := lastName(childRule(ctx, gen.PlSqlParserRULE_function_name))
+	c.checkUnitEnd(ctx, "FUNCTION", name)
+	c.checkReturns(ctx, name)
+}
+
+// EnterProcedure_body checks the name after the END of a procedure declared in
+// a package body or block
+func (c *blockChecker) EnterProcedure_body(ctx *gen.Procedure_bodyContext) {
+	c.checkUnitEnd(ctx, "PROCEDURE", lastName(childRule(ctx, gen.PlSqlParserRULE_identifier)))
+}
+
+// EnterFunction_body checks the name after the END of a function declared in a
+// package body or block and that it returns
+func (c *blockChecker) EnterFunction_body(ctx *gen.Function_bodyContext) {
+	name := lastName(childRule(ctx, gen.PlSqlParserRULE_identifier))
+	c.checkUnitEnd(ctx, "FUNCTION", name)
+	c.checkReturns(ctx, name)
+}
+
+// EnterCreate_package checks the name after the END of a package
+func (c *blockChecker) EnterCreate_package(ctx *gen.Create_packageContext) {
+	c.checkPackageEnd(ctx, "PACKAGE")
+}
+
+// EnterCreate_package_body checks the name after the END of a package body
+func (c *blockChecker) EnterCreate_package_body(ctx *gen.Create_package_bodyContext) {
+	c.checkPackageEnd(ctx, "PACKAGE BODY")
+}
+
+// EnterBody checks the label after the END of a labeled block. The END of a
+// subprogram is checked with the subprogram.
+func (c *blockChecker) EnterBody(ctx *gen.BodyContext) {
+	end := childRule(ctx, gen.PlSqlParserRULE_label_name)
+	if end == nil {
+		return
+	}
+
+	// A block is a statement of its own, or the body of a DECLARE block
+	stmt := ctx.GetParent()
+	if ruleIndex(stmt) == gen.PlSqlParserRULE_block {
+		stmt = stmt.GetParent()
+	}
+	if ruleIndex(stmt) != gen.PlSqlParserRULE_statement {
+		return
+	}
+
+	labels := labelsBefore(stmt)
+	if !containsName(labels, end.GetText()) {
+		c.errors.addSemantic(end.GetStart(), CodeEndLabelMismatch, labelMismatch("END "+end.GetText(), "block", labels))
+	}
+}
+
+// EnterLoop_statement checks the label after the END LOOP of a loop
+func (c *blockChecker) EnterLoop_statement(ctx *gen.Loop_statementContext) {
+	end := childRule(ctx, gen.PlSqlParserRULE_label_name)
+	if end == nil {
+		return
+	}
+
+	// The label is read as part of the loop or as a statement before it
+	var labels []string
+	for _, label := range childRules(ctx, gen.PlSqlParserRULE_label_declaration) {
+		labels = append(labels, labelOf(label))
+	}
+	if stmt := ctx.GetParent(); ruleIndex(stmt) == gen.PlSqlParserRULE_statement {
+		labels = append(labels, labelsBefore(stmt)...)
+	}
+
+	if !containsName(labels, end.GetText()) {
+		c.errors.addSemantic(end.GetStart(), CodeLoopLabelMismatch, labelMismatch("END LOOP "+end.GetText(), "loop", labels))
+	}
+}
+
+// EnterRaise_statement checks that a RAISE without an exception re-raises the
+// exception of an enclosing handler
+func (c *blockChecker) EnterRaise_statement(ctx *gen.Raise_statementContext) {
+	if childRule(ctx, gen.PlSqlParserRULE_exception_name) != nil {
+		return
+	}
+
+	if !inHandler(ctx) {
+		c.errors.addSemantic(ctx.GetStart(), CodeRaiseOutsideHandler, "RAISE without an exception name is only allowed in an exception handler")
+	}
+}
+
+// inHandler reports whether a statement is inside an exception handler of the
+// subprogram or block it belongs to
+func inHandler(ctx antlr.Tree) bool {
+	for parent := ctx.GetParent(); parent != nil; parent = parent.GetParent() {
+		switch ruleIndex(parent) {
+		case gen.PlSqlParserRULE_exception_handler:
+			return true
+		case gen.PlSqlParserRULE_procedure_body, gen.PlSqlParserRULE_function_body,
+			gen.PlSqlParserRULE_create_procedure_body, gen.PlSqlParserRULE_create_function_body:
+			// The handlers around the declaration of a subprogram do not count
+			return false
+		}
+	}
+	return false
+}
+
+// EnterReturn_statement checks that a RETURN can be reached
+func (c *blockChecker) EnterReturn_statement(ctx *gen.Return_statementContext) {
+	stmt := ctx.GetParent()
+	seq := stmt.GetParent()
+	if ruleIndex(stmt) != gen.PlSqlParserRULE_statement || seq == nil {
+		return
+	}
+
+	// The statement before the RETURN, unless a label makes it a GOTO target
+	var previous antlr.Tree
+	for _, child := range seq.GetChildren() {
+		if child == stmt {
+			break
+		}
+		switch ruleIndex(child) {
+		case gen.PlSqlParserRULE_statement, gen.PlSqlParserRULE_declare_block:
+			previous = child
+		case gen.PlSqlParserRULE_label_declaration:
+			previous = nil
+		}
+	}
+
+	if previous != nil && terminates(previous) {
+		c.errors.addSemantic(ctx.GetStart(), CodeUnreachableReturn, "RETURN cannot be reached, the statement before it never completes")
+	}
+}
+
+// checkUnitEnd reports a name after the END of a subprogram that is not its name
+func (c *blockChecker) checkUnitEnd(ctx antlr.Tree, kind, name string) {
+	body := childRule(ctx, gen.PlSqlParserRULE_body)
+	if body == nil || name == "" {
+		return
+	}
+	end := childRule(body, gen.PlSqlParserRULE_label_name)
+	if end != nil && !sameName(end.GetText(), name) {
+		c.errors.addSemantic(end.GetStart(), CodeEndLabelMismatch, fmt.Sprintf("END %s does not match the name of %s %s", end.GetText(), kind, name))
+	}
+}
+
+// checkPackageEnd reports a name after the END of a package that is not its name
+func (c *blockChecker) checkPackageEnd(ctx antlr.Tree, kind string) {
+	names := childRules(ctx, gen.PlSqlParserRULE_package_name)
+	if len(names) == 2 && !sameName(names[1].GetText(), names[0].GetText()) {
+		end := names[1]
+		c.errors.addSemantic(end.GetStart(), CodeEndLabelMismatch, fmt.Sprintf("END %s does not match the name of %s %s", end.GetText(), kind, names[0].GetText()))
+	}
+}
+
+// checkReturns reports a function whose statements or handlers can complete
+// without returning a value, which fails at run time with ORA-06503
+func (c *blockChecker) checkReturns(ctx antlr.Tree, name string) {
+	body := childRule(ctx, gen.PlSqlParserRULE_body)
+	if body == nil || terminates(body) {
+		return
+	}
+
+	end := childToken(body, gen.PlSqlLexerEND)
+	if end == nil {
+		return
+	}
+	c.errors.addSemantic(end, CodeMissingReturn, fmt.Sprintf("function %s can reach its END without a RETURN", name))
+}
+
+// terminates reports whether a statement never completes normally, as it
+// returns, raises, jumps away or loops forever. Calls other than
+// RAISE_APPLICATION_ERROR are assumed to complete.
+func terminates(tree antlr.Tree) bool {
+	switch ruleIndex(tree) {
+	case gen.PlSqlParserRULE_statement, gen.PlSqlParserRULE_block, gen.PlSqlParserRULE_case_statement:
+		for _, child := range tree.GetChildren() {
+			if ruleIndex(child) >= 0 {
+				return terminates(child)
+			}
+		}
+		return false
+	case gen.PlSqlParserRULE_return_statement, gen.PlSqlParserRULE_raise_statement, gen.PlSqlParserRULE_goto_statement:
+		return true
+	case gen.PlSqlParserRULE_call_statement:
+		name, _, _ := strings.Cut(tree.(antlr.ParseTree).GetText(), "(")
+		return strings.EqualFold(name, "RAISE_APPLICATION_ERROR")
+	case gen.PlSqlParserRULE_body, gen.PlSqlParserRULE_declare_block:
+		// The handlers end the block as well
+		if !terminates(childRule(tree, gen.PlSqlParserRULE_seq_of_statements)) {
+			return false
+		}
+		for _, handler := range childRules(tree, gen.PlSqlParserRULE_exception_handler) {
+			if !terminates(childRule(handler, gen.PlSqlParserRULE_seq_of_statements)) {
+				return false
+			}
+		}
+		return true
+	case gen.PlSqlParserRULE_seq_of_statements:
+		var last antlr.Tree
+		for _, child := range tree.GetChildren() {
+			if ruleIndex(child) >= 0 {
+				last = child
+			}
+		}
+		return last != nil && terminates(last)
+	case gen.PlSqlParserRULE_if_statement:
+		if childRule(tree, gen.PlSqlParserRULE_else_part) == nil {
+			return false
+		}
+		return allTerminate(tree, gen.PlSqlParserRULE_seq_of_statements, gen.PlSqlParserRULE_elsif_part, gen.PlSqlParserRULE_else_part)
+	case gen.PlSqlParserRULE_simple_case_statement:
+		return childRule(tree, gen.PlSqlParserRULE_case_else_part) != nil &&
+			allTerminate(tree, gen.PlSqlParserRULE_simple_case_when_part, gen.PlSqlParserRULE_case_else_part)
+	case gen.PlSqlParserRULE_searched_case_statement:
+		return childRule(tree, gen.PlSqlParserRULE_case_else_part) != nil &&
+			allTerminate(tree, gen.PlSqlParserRULE_searched_case_when_part, gen.PlSqlParserRULE_case_else_part)
+	case gen.PlSqlParserRULE_elsif_part, gen.PlSqlParserRULE_else_part, gen.PlSqlParserRULE_simple_case_when_part,
+		gen.PlSqlParserRULE_searched_case_when_part, gen.PlSqlParserRULE_case_else_part:
+		return terminates(childRule(tree, gen.PlSqlParserRULE_seq_of_statements))
+	case gen.PlSqlParserRULE_loop_statement:
+		// A plain LOOP without an EXIT runs until it returns or raises
+		if childToken(tree, gen.PlSqlLexerWHILE) != nil || childToken(tree, gen.PlSqlLexerFOR) != nil {
+			return false
+		}
+		return !containsRule(tree, gen.PlSqlParserRULE_exit_statement)
+	}
+	return false
+}
+
+// allTerminate reports whether every child of tree with one of the given rules
+// terminates
+func allTerminate(tree antlr.Tree, rules ...int) bool {
+	for _, child := range tree.GetChildren() {
+		for _, rule := range rules {
+			if ruleIndex(child) == rule && !terminates(child) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// labelsBefore returns the labels declared just before a statement
+func labelsBefore(stmt antlr.Tree) []string {
+	seq := stmt.GetParent()
+	if seq == nil {
+		return nil
+	}
+
+	var labels []string
+	for _, child := range seq.GetChildren() {
+		if child == stmt {
+			return labels
+		}
+		switch ruleIndex(child) {
+		case gen.PlSqlParserRULE_label_declaration:
+			labels = append(labels, labelOf(child))
+		case -1:
+			// Terminals such as ; do not separate labels from their statement
+		default:
+			labels = nil
+		}
+	}
+	return nil
+}
+
+// labelOf returns the name of a label declaration
+func labelOf(label antlr.Tree) string {
+	if name := childRule(label, gen.PlSqlParserRULE_label_name); name != nil {
+		return name.GetText()
+	}
+	return ""
+}
+
+// labelMismatch describes an END label that matches none of the labels of
+// what it closes
+func labelMismatch(end, kind string, labels []string) string {
+	if len(labels) == 0 {
+		return fmt.Sprintf("%s closes a %s without a label", end, kind)
+	}
+	return fmt.Sprintf("%s does not match the %s label <<%s>>", end, kind, strings.Join(labels, ">> <<"))
+}
+
+// lastName returns the last part of a possibly qualified name, such as p of
+// hr.p
+func lastName(name antlr.ParserRuleContext) string {
+	if name == nil {
+		return ""
+	}
+	text := name.GetText()
+	return text[strings.LastIndexByte(text, '.')+1:]
+}
+
+// sameName reports whether two identifiers name the same thing: unquoted
+// identifiers are not case sensitive
+func sameName(a, b string) bool {
+	normalize := func(name string) string {
+		if strings.HasPrefix(name, `"`) {
+			return strings.Trim(name, `"`)
+		}
+		return strings.ToUpper(name)
+	}
+	return normalize(a) == normalize(b)
+}
+
+// containsName reports whether names holds name
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if sameName(n, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleIndex returns the grammar rule of a tree node, or -1 for tokens
+func ruleIndex(tree antlr.Tree) int {
+	if ctx, ok := tree.(antlr.RuleContext); ok {
+		return ctx.GetRuleIndex()
+	}
+	return -1
+}
+
+// childRules returns the direct children of tree matching a grammar rule
+func childRules(tree antlr.Tree, rule int) []antlr.ParserRuleContext {
+	var found []antlr.ParserRuleContext
+	for _, child := range tree.GetChildren() {
+		if ctx, ok := child.(antlr.ParserRuleContext); ok && ctx.GetRuleIndex() == rule {
+			found = append(found, ctx)
+		}
+	}
+	return found
+}
+
+// childRule returns the first direct child of tree matching a grammar rule, or nil
+func childRule(tree antlr.Tree, rule int) antlr.ParserRuleContext {
+	if found := childRules(tree, rule); len(found) > 0 {
+		return found[0]
+	}
+	return nil
+}
+
+// childToken returns the first direct child token of tree with a token type, or nil
+func childToken(tree antlr.Tree, tokenType int) antlr.Token {
+	for _, child := range tree.GetChildren() {
+		if terminal, ok := child.(antlr.TerminalNode); ok && terminal.GetSymbol().GetTokenType() == tokenType {
+			return terminal.GetSymbol()
+		}
+	}
+	return nil
+}
+
+// containsRule reports whether a rule occurs anywhere below tree
+func containsRule(tree antlr.Tree, rule int) bool {
+	for _, child := range tree.GetChildren() {
+		if ruleIndex(child) == rule || containsRule(child, rule) {
+			return true
+		}
+	}
+	return false
+}
+
+// addSemantic records a block structure error at a token
+func (l *CustomErrorListener) addSemantic(tok antlr.Token, code, message string) {
+	if len(l.Errors) >= l.MaxErrors {
+		return
+	}
+
+	line, column := tok.GetLine(), tok.GetColumn()
+	context := ""
+	if l.SourceText != "" {
+		context = l.extractErrorContext(line, column)
+	}
+	l.Errors = append(l.Errors, SyntaxError{
+		Line:           line,
+		Column:         column,
+		Message:        message,
+		TokenText:      tok.GetText(),
+		Token:          l.errorToken(tok),
+		Context:        context,
+		Kind:           SemanticError,
+		Code:           code,
+		StatementIndex: -1,
+	})
+}
@@ -0,0 +1,144 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestBlockChecks(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		code    string
+		message string
+		line    int
+		column  int
+	}{
+		{
+			name:    "procedure END name",
+			input:   "CREATE OR REPLACE PROCEDURE foo IS\nBEGIN\n  NULL;\nEND wrong_name;\n/\n",
+			code:    CodeEndLabelMismatch,
+			message: "END wrong_name does not match the name of PROCEDURE foo",
+			line:    4,
+			column:  4,
+		},
+		{
+			name:    "package END name",
+			input:   "CREATE OR REPLACE PACKAGE pkg IS\n  PROCEDURE p;\nEND other_pkg;\n/\n",
+			code:    CodeEndLabelMismatch,
+			message: "END other_pkg does not match the name of PACKAGE pkg",
+			line:    3,
+			column:  4,
+		},
+		{
+			name:    "packaged procedure END name",
+			input:   "CREATE OR REPLACE PACKAGE BODY pkg IS\n  PROCEDURE p IS\n  BEGIN\n    NULL;\n  END q;\nEND pkg;\n/\n",
+			code:    CodeEndLabelMismatch,
+			message: "END q does not match the name of PROCEDURE p",
+			line:    5,
+			column:  6,
+		},
+		{
+			name:    "block label",
+			input:   "BEGIN\n  <<outer>>\n  BEGIN\n    NULL;\n  END inner;\nEND;\n/\n",
+			code:    CodeEndLabelMismatch,
+			message: "END inner does not match the block label <<outer>>",
+			line:    5,
+			column:  6,
+		},
+		{
+			name:    "unlabeled block",
+			input:   "BEGIN\n  BEGIN\n    NULL;\n  END inner;\nEND;\n/\n",
+			code:    CodeEndLabelMismatch,
+			message: "END inner closes a block without a label",
+			line:    4,
+			column:  6,
+		},
+		{
+			name:    "loop label",
+			input:   "BEGIN\n  <<rows_loop>>\n  FOR r IN 1 .. 10 LOOP\n    NULL;\n  END LOOP other_label;\nEND;\n/\n",
+			code:    CodeLoopLabelMismatch,
+			message: "END LOOP other_label does not match the loop label <<rows_loop>>",
+			line:    5,
+			column:  11,
+		},
+		{
+			name:    "RAISE outside a handler",
+			input:   "BEGIN\n  IF x IS NULL THEN\n    RAISE;\n  END IF;\nEND;\n/\n",
+			code:    CodeRaiseOutsideHandler,
+			message: "RAISE without an exception name is only allowed in an exception handler",
+			line:    3,
+			column:  4,
+		},
+		{
+			name:    "missing RETURN",
+			input:   "CREATE OR REPLACE FUNCTION f(x NUMBER) RETURN NUMBER IS\nBEGIN\n  IF x > 0 THEN\n    RETURN 1;\n  END IF;\nEND f;\n/\n",
+			code:    CodeMissingReturn,
+			message: "function f can reach its END without a RETURN",
+			line:    6,
+			column:  0,
+		},
+		{
+			name:    "missing RETURN in a handler",
+			input:   "CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\nBEGIN\n  RETURN 1;\nEXCEPTION\n  WHEN no_data_found THEN\n    log_error;\nEND;\n/\n",
+			code:    CodeMissingReturn,
+			message: "function f can reach its END without a RETURN",
+			line:    7,
+			column:  0,
+		},
+		{
+			name:    "unreachable RETURN",
+			input:   "CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\nBEGIN\n  RAISE_APPLICATION_ERROR(-20001, 'not implemented');\n  RETURN NULL;\nEND;\n/\n",
+			code:    CodeUnreachableReturn,
+			message: "RETURN cannot be reached, the statement before it never completes",
+			line:    4,
+			column:  2,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, syntaxErrors, err := Parse(tc.input, ParseOptions{MaxErrors: 10, BlockChecks: true})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(syntaxErrors) != 1 {
+				t.Fatalf("Expected 1 error, got %+v", syntaxErrors)
+			}
+
+			got := syntaxErrors[0]
+			if got.Kind != SemanticError || got.Code != tc.code || got.Message != tc.message {
+				t.Errorf("Expected %s %q, got %s %s %q", tc.code, tc.message, got.Kind, got.Code, got.Message)
+			}
+			if got.Line != tc.line || got.Column != tc.column {
+				t.Errorf("Expected the error at %d:%d, got %d:%d", tc.line, tc.column, got.Line, got.Column)
+			}
+			if got.StatementIndex != 0 {
+				t.Errorf("Expected the error in statement 0, got %d", got.StatementIndex)
+			}
+
+			// The checks are off by default
+			if _, syntaxErrors, _ := Parse(tc.input, ParseOptions{MaxErrors: 10}); len(syntaxErrors) != 0 {
+				t.Errorf("Expected no errors without block checks, got %+v", syntaxErrors)
+			}
+		})
+	}
+}
+
+func TestBlockChecks_Valid(t *testing.T) {
+	inputs := []string{
+		"CREATE OR REPLACE PROCEDURE hr.foo IS\nBEGIN\n  NULL;\nEND foo;\n/\n",
+		"CREATE OR REPLACE PACKAGE BODY pkg IS\n  FUNCTION f RETURN NUMBER IS\n  BEGIN\n    RETURN 1;\n  END F;\nEND pkg;\n/\n",
+		"BEGIN\n  <<outer>>\n  DECLARE\n    x NUMBER;\n  BEGIN\n    <<rows_loop>>\n    LOOP\n      EXIT rows_loop;\n    END LOOP rows_loop;\n  END outer;\nEND;\n/\n",
+		"BEGIN\n  run;\nEXCEPTION\n  WHEN OTHERS THEN\n    log_error;\n    RAISE;\nEND;\n/\n",
+		"CREATE OR REPLACE FUNCTION f(x NUMBER) RETURN NUMBER IS\nBEGIN\n  IF x > 0 THEN\n    RETURN 1;\n  ELSIF x < 0 THEN\n    RAISE value_error;\n  ELSE\n    RETURN 0;\n  END IF;\nEXCEPTION\n  WHEN OTHERS THEN\n    RAISE;\nEND;\n/\n",
+		"CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\nBEGIN\n  LOOP\n    RETURN 1;\n  END LOOP;\nEND;\n/\n",
+		"CREATE OR REPLACE FUNCTION f RETURN NUMBER IS\nBEGIN\n  GOTO done;\n  <<done>>\n  RETURN 1;\nEND;\n/\n",
+	}
+
+	for _, input := range inputs {
+		_, syntaxErrors, err := Parse(input, ParseOptions{MaxErrors: 10, BlockChecks: true})
+		if err != nil || len(syntaxErrors) != 0 {
+			t.Errorf("Expected no errors for %q, got %+v, %v", input, syntaxErrors, err)
+		}
+	}
+}
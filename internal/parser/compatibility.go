@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// CodeUnsupportedSyntax is the code of the warnings about syntax that the
+// target release does not support
+const CodeUnsupportedSyntax = "PLS-SPLIT-1008"
+
+// feature is syntax introduced in a later release than some targets
+type feature struct {
+	name    string  // Name of the syntax, the text of its first token when empty
+	version Version // First release supporting it; 12.2 features count as 18c
+}
+
+// featureRules maps grammar rules to the syntax they hold. The rules guarded
+// by a version predicate come first, then those of versionedRules and then
+// syntax that the grammar accepts for every release.
+var featureRules = map[int]feature{
+	gen.PlSqlParserRULE_editionable_noneditionable: {"", Version12c},
+	gen.PlSqlParserRULE_alter_view_editionable:     {"", Version12c},
+	gen.PlSqlParserRULE_library_editionable:        {"", Version12c},
+	gen.PlSqlParserRULE_library_debug:              {"CREATE LIBRARY ... DEBUG", Version12c},
+	gen.PlSqlParserRULE_unified_auditing:           {"unified auditing", Version12c},
+	gen.PlSqlParserRULE_audit_direct_path:          {"AUDIT DIRECT_PATH", Version12c},
+	gen.PlSqlParserRULE_audit_container_clause:     {"", Version12c},
+	gen.PlSqlParserRULE_period_definition:          {"PERIOD FOR", Version12c},
+	gen.PlSqlParserRULE_with_plsql_declaration:     {"PL/SQL in WITH", Version12c},
+	gen.PlSqlParserRULE_if_exists:                  {"IF EXISTS", Version23ai},
+	gen.PlSqlParserRULE_if_not_exists:              {"IF NOT EXISTS", Version23ai},
+	gen.PlSqlParserRULE_annotations_clause:         {"", Version23ai},
+	gen.PlSqlParserRULE_create_domain:              {"CREATE DOMAIN", Version23ai},
+	gen.PlSqlParserRULE_drop_domain:                {"DROP DOMAIN", Version23ai},
+	gen.PlSqlParserRULE_column_domain_clause:       {"", Version23ai},
+	gen.PlSqlParserRULE_create_json_duality_view:   {"JSON relational duality view", Version23ai},
+	gen.PlSqlParserRULE_no_from_clause:             {"SELECT without FROM", Version23ai},
+	gen.PlSqlParserRULE_table_value_rows:           {"VALUES with several rows", Version23ai},
+
+	gen.PlSqlParserRULE_offset_clause:   {"", Version12c},
+	gen.PlSqlParserRULE_fetch_clause:    {"FETCH FIRST", Version12c},
+	gen.PlSqlParserRULE_identity_clause: {"identity column", Version12c},
+
+	gen.PlSqlParserRULE_json_table_clause:       {"", Version12c},
+	gen.PlSqlParserRULE_listagg_overflow_clause: {"LISTAGG ... ON OVERFLOW", Version18c},
+}
+
+// jsonFunctions maps the first token of a json_function to the release
+// introducing it
+var jsonFunctions = map[int]Version{
+	gen.PlSqlLexerJSON_QUERY:     Version12c,
+	gen.PlSqlLexerJSON_VALUE:     Version12c,
+	gen.PlSqlLexerJSON_ARRAY:     Version18c,
+	gen.PlSqlLexerJSON_ARRAYAGG:  Version18c,
+	gen.PlSqlLexerJSON_OBJECT:    Version18c,
+	gen.PlSqlLexerJSON_OBJECTAGG: Version18c,
+	gen.PlSqlLexerJSON_SERIALIZE: Version19c,
+	gen.PlSqlLexerJSON_TRANSFORM: Version21c,
+}
+
+// builtinFunctions maps the built-in functions that are called like any other
+// function to the release introducing them
+var builtinFunctions = map[string]Version{
+	"APPROX_COUNT_DISTINCT":        Version12c,
+	"JSON_EXISTS":                  Version12c,
+	"STANDARD_HASH":                Version12c,
+	"APPROX_COUNT_DISTINCT_AGG":    Version18c,
+	"APPROX_COUNT_DISTINCT_DETAIL": Version18c,
+	"APPROX_MEDIAN":                Version18c,
+	"APPROX_COUNT":                 Version18c,
+	"APPROX_SUM":                   Version18c,
+	"JSON_DATAGUIDE":               Version18c,
+	"TO_APPROX_COUNT_DISTINCT":     Version18c,
+	"TO_UTC_TIMESTAMP_TZ":          Version18c,
+	"VALIDATE_CONVERSION":          Version18c,
+	"JSON_MERGEPATCH":              Version19c,
+	"JSON_SCALAR":                  Version21c,
+}
+
+// CompatibilityChecker finds the syntax of a script that a target release
+// does not support. It is passed to Parse in ParseOptions.Listeners, with
+// AllVersions set so that the script parses, and its Warnings read after the
+// parse.
+type CompatibilityChecker struct {
+	*gen.BasePlSqlParserListener
+	index          *source.Index
+	target         Version
+	statementIndex int
+	warnings       []Warning
+}
+
+// NewCompatibilityChecker creates a checker for input targeting a release
+func NewCompatibilityChecker(input string, target Version) *CompatibilityChecker {
+	return &CompatibilityChecker{
+		BasePlSqlParserListener: &gen.BasePlSqlParserListener{},
+		index:                   source.NewIndex(input),
+		target:                  target.resolve(),
+		statementIndex:          -1,
+	}
+}
+
+// SetStatementIndex implements StatementAware
+func (c *CompatibilityChecker) SetStatementIndex(index int) {
+	c.statementIndex = index
+}
+
+// EnterEveryRule reports the rules of syntax that the target does not support
+func (c *CompatibilityChecker) EnterEveryRule(ctx antlr.ParserRuleContext) {
+	f, ok := featureRules[ctx.GetRuleIndex()]
+	if !ok {
+		return
+	}
+
+	// Rules matching no token, such as no_from_clause, point at their parent
+	at := ctx
+	if ctx.GetChildCount() == 0 {
+		if parent, ok := ctx.GetParent().(antlr.ParserRuleContext); ok {
+			at = parent
+		}
+	}
+	c.check(at.GetStart(), at.GetStop(), f)
+}
+
+// EnterTable_ref_aux_internal_values reports a table value constructor
+func (c *CompatibilityChecker) EnterTable_ref_aux_internal_values(ctx *gen.Table_ref_aux_internal_valuesContext) {
+	c.check(ctx.GetStart(), ctx.GetStop(), feature{"VALUES in FROM", Version23ai})
+}
+
+// EnterJson_function reports a JSON function
+func (c *CompatibilityChecker) EnterJson_function(ctx *gen.Json_functionContext) {
+	if version, ok := jsonFunctions[ctx.GetStart().GetTokenType()]; ok {
+		c.check(ctx.GetStart(), ctx.GetStop(), feature{"", version})
+	}
+}
+
+// EnterJson_condition reports IS JSON and JSON_EQUAL
+func (c *CompatibilityChecker) EnterJson_condition(ctx *gen.Json_conditionContext) {
+	if ctx.GetStart().GetTokenType() == gen.PlSqlLexerJSON_EQUAL {
+		c.check(ctx.GetStart(), ctx.GetStop(), feature{"", Version18c})
+	} else {
+		c.check(ctx.GetStart(), ctx.GetStop(), feature{"IS JSON", Version12c})
+	}
+}
+
+// EnterGeneral_element reports a call to a built-in function. Qualified
+// calls, such as pkg.approx_sum(x), are to functions of the script.
+func (c *CompatibilityChecker) EnterGeneral_element(ctx *gen.General_elementContext) {
+	parts := childRules(ctx, gen.PlSqlParserRULE_general_element_part)
+	if len(parts) != 1 || childRule(parts[0], gen.PlSqlParserRULE_function_argument) == nil {
+		return
+	}
+	name := childRule(parts[0], gen.PlSqlParserRULE_id_expression)
+	if name == nil || childToken(name, gen.PlSqlLexerDELIMITED_ID) != nil {
+		return
+	}
+	if version, ok := builtinFunctions[strings.ToUpper(name.GetText())]; ok {
+		c.check(name.GetStart(), name.GetStop(), feature{"", version})
+	}
+}
+
+// check reports syntax from start to stop if the target does not support it
+func (c *CompatibilityChecker) check(start, stop antlr.Token, f feature) {
+	if c.target.AtLeast(f.version) {
+		return
+	}
+	if stop == nil || stop.GetTokenIndex() < start.GetTokenIndex() {
+		stop = start
+	}
+
+	name := f.name
+	if name == "" {
+		name = strings.ToUpper(start.GetText())
+	}
+	c.warnings = append(c.warnings, Warning{
+		Code:           CodeUnsupportedSyntax,
+		Message:        fmt.Sprintf("%s requires Oracle %s or later, but the target is %s", name, f.version, c.target),
+		Line:           start.GetLine(),
+		Column:         start.GetColumn(),
+		StartOffset:    c.index.ByteOffset(start.GetStart()),
+		EndOffset:      c.index.ByteOffset(stop.GetStop() + 1),
+		StatementIndex: c.statementIndex,
+		Required:       f.version,
+	})
+}
+
+// Warnings returns the warnings of the walk in script order
+func (c *CompatibilityChecker) Warnings() []Warning {
+	warnings := append([]Warning(nil), c.warnings...)
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].StartOffset < warnings[j].StartOffset
+	})
+	return warnings
+}
@@ -0,0 +1,139 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// checkCompatibility parses input with the syntax of every release and
+// returns the warnings of a CompatibilityChecker for target
+func checkCompatibility(t *testing.T, input string, target Version) []Warning {
+	t.Helper()
+	checker := NewCompatibilityChecker(input, target)
+	_, syntaxErrors, err := Parse(input, ParseOptions{
+		MaxErrors:   10,
+		Version:     target,
+		AllVersions: true,
+		CCFlags:     map[string]any{},
+		Listeners:   []antlr.ParseTreeListener{checker},
+	})
+	if err != nil || len(syntaxErrors) != 0 {
+		t.Fatalf("Expected %q to parse, got %+v, %v", input, syntaxErrors, err)
+	}
+	return checker.Warnings()
+}
+
+func TestCompatibilityChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		target   Version
+		message  string
+		required Version
+		line     int
+		column   int
+	}{
+		{
+			name:     "FETCH FIRST",
+			input:    "SELECT * FROM employees FETCH FIRST 5 ROWS ONLY;\n",
+			target:   Version11g,
+			message:  "FETCH FIRST requires Oracle 12c or later, but the target is 11g",
+			required: Version12c,
+			line:     1,
+			column:   24,
+		},
+		{
+			name:     "identity column",
+			input:    "CREATE TABLE t (id NUMBER GENERATED ALWAYS AS IDENTITY);\n",
+			target:   Version11g,
+			message:  "identity column requires Oracle 12c or later, but the target is 11g",
+			required: Version12c,
+			line:     1,
+			column:   26,
+		},
+		{
+			name:     "LISTAGG ON OVERFLOW",
+			input:    "SELECT LISTAGG(name, ',' ON OVERFLOW TRUNCATE) WITHIN GROUP (ORDER BY name) FROM employees;\n",
+			target:   Version12c,
+			message:  "LISTAGG ... ON OVERFLOW requires Oracle 18c or later, but the target is 12c",
+			required: Version18c,
+			line:     1,
+			column:   25,
+		},
+		{
+			name:     "JSON function",
+			input:    "SELECT JSON_OBJECT('id' VALUE id) FROM employees;\n",
+			target:   Version12c,
+			message:  "JSON_OBJECT requires Oracle 18c or later, but the target is 12c",
+			required: Version18c,
+			line:     1,
+			column:   7,
+		},
+		{
+			name:     "APPROX_COUNT_DISTINCT",
+			input:    "SELECT approx_count_distinct(id) FROM employees;\n",
+			target:   Version11g,
+			message:  "APPROX_COUNT_DISTINCT requires Oracle 12c or later, but the target is 11g",
+			required: Version12c,
+			line:     1,
+			column:   7,
+		},
+		{
+			name:     "PL/SQL in WITH",
+			input:    "WITH FUNCTION double_it(n NUMBER) RETURN NUMBER IS\nBEGIN\n  RETURN n * 2;\nEND;\nSELECT double_it(id) FROM employees\n/\n",
+			target:   Version11g,
+			message:  "PL/SQL in WITH requires Oracle 12c or later, but the target is 11g",
+			required: Version12c,
+			line:     1,
+			column:   5,
+		},
+		{
+			name:     "IF NOT EXISTS",
+			input:    "CREATE TABLE IF NOT EXISTS t (id NUMBER);\n",
+			target:   Version19c,
+			message:  "IF NOT EXISTS requires Oracle 23ai or later, but the target is 19c",
+			required: Version23ai,
+			line:     1,
+			column:   13,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := checkCompatibility(t, tc.input, tc.target)
+			if len(warnings) != 1 {
+				t.Fatalf("Expected 1 warning, got %+v", warnings)
+			}
+
+			got := warnings[0]
+			if got.Code != CodeUnsupportedSyntax || got.Message != tc.message || got.Required != tc.required {
+				t.Errorf("Expected %q requiring %s, got %s %q requiring %s", tc.message, tc.required, got.Code, got.Message, got.Required)
+			}
+			if got.Line != tc.line || got.Column != tc.column || got.StatementIndex != 0 {
+				t.Errorf("Expected the warning at %d:%d in statement 0, got %d:%d in %d", tc.line, tc.column, got.Line, got.Column, got.StatementIndex)
+			}
+
+			// The release that introduced the syntax supports it
+			if warnings := checkCompatibility(t, tc.input, tc.required); len(warnings) != 0 {
+				t.Errorf("Expected no warnings for %s, got %+v", tc.required, warnings)
+			}
+		})
+	}
+}
+
+func TestCompatibilityChecker_ConditionalCompilation(t *testing.T) {
+	input := "BEGIN\n" +
+		"$IF DBMS_DB_VERSION.VER_LE_11 $THEN\n" +
+		"  SELECT COUNT(DISTINCT id) INTO n FROM employees;\n" +
+		"$ELSE\n" +
+		"  SELECT APPROX_COUNT_DISTINCT(id) INTO n FROM employees;\n" +
+		"$END\n" +
+		"END;\n/\n"
+
+	for _, target := range []Version{Version11g, Version19c} {
+		if warnings := checkCompatibility(t, input, target); len(warnings) != 0 {
+			t.Errorf("Expected no warnings for %s, got %+v", target, warnings)
+		}
+	}
+}
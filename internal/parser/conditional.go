@@ -0,0 +1,639 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Branch is one branch of a conditional compilation $IF directive
+type Branch struct {
+	Directive   string // $IF, $ELSIF or $ELSE
+	Condition   string // Text of the condition, empty for $ELSE
+	Line        int    // Line of the directive
+	Column      int    // Column of the directive
+	StartOffset int    // Byte offset of the branch's code, just after $THEN or $ELSE
+	EndOffset   int    // Byte offset just past the branch's code, at the next directive
+	Depth       int    // Number of $IF directives that enclose the branch's $IF
+	Live        bool   // Whether the branch is selected and all enclosing branches are live
+}
+
+// directiveFilter is a token source that resolves conditional compilation.
+// Directives, their conditions and the code of branches that are not selected
+// are moved to the hidden channel, so the parser sees the unit as it would be
+// compiled. Inquiry directives in live code become identifiers.
+//
+// Without flags the first branch of every $IF is selected, which accepts the
+// directives without evaluating them. With flags, conditions are evaluated
+// like the PL/SQL preprocessor does, and live $ERROR directives and invalid
+// conditions are reported.
+type directiveFilter struct {
+	*gen.PlSqlLexer
+	flags   map[string]any // Inquiry directive and static constant values, nil to select first branches
+	version Version        // Release whose DBMS_DB_VERSION constants conditions see
+
+	lookahead antlr.Token   // Token read past the end of a directive
+	queue     []antlr.Token // Tokens of a directive, ready to be returned
+	frames    []ccFrame     // Open $IF directives, innermost last
+	branches  []ccBranch
+	errors    []ccError
+}
+
+// ccFrame is an open $IF directive
+type ccFrame struct {
+	live   bool // Whether the current branch is live
+	parent bool // Whether the code around the $IF is live
+	taken  bool // Whether a branch has been selected
+	branch int  // Index of the current branch
+}
+
+// ccBranch is a Branch with character indexes instead of byte offsets
+type ccBranch struct {
+	Branch
+	start, stop int
+}
+
+// ccError is a directive error
+type ccError struct {
+	line, column int
+	msg          string
+}
+
+// newDirectiveFilter wraps lexer. A nil flags map selects first branches.
+func newDirectiveFilter(lexer *gen.PlSqlLexer, flags map[string]any, version Version) *directiveFilter {
+	f := &directiveFilter{PlSqlLexer: lexer}
+	f.reset(flags, version)
+	return f
+}
+
+// reset prepares the filter for a new script
+func (f *directiveFilter) reset(flags map[string]any, version Version) {
+	f.flags = flags
+	f.version = version
+	f.lookahead = nil
+	f.queue = f.queue[:0]
+	f.frames = f.frames[:0]
+	f.branches = nil
+	f.errors = nil
+}
+
+// evaluating reports whether conditions are evaluated
+func (f *directiveFilter) evaluating() bool {
+	return f.flags != nil
+}
+
+// live reports whether code at the current position is compiled
+func (f *directiveFilter) live() bool {
+	return len(f.frames) == 0 || f.frames[len(f.frames)-1].live
+}
+
+// next returns the next token of the lexer
+func (f *directiveFilter) next() antlr.Token {
+	if tok := f.lookahead; tok != nil {
+		f.lookahead = nil
+		return tok
+	}
+	return f.PlSqlLexer.NextToken()
+}
+
+// NextToken returns the next token with directives resolved
+func (f *directiveFilter) NextToken() antlr.Token {
+	if len(f.queue) > 0 {
+		tok := f.queue[0]
+		f.queue = f.queue[1:]
+		return tok
+	}
+
+	tok := f.next()
+	switch tok.GetTokenType() {
+	case gen.PlSqlLexerCC_IF, gen.PlSqlLexerCC_ELSIF:
+		return f.conditional(tok)
+	case gen.PlSqlLexerCC_ELSE:
+		if len(f.frames) == 0 {
+			f.errorf(tok, "$ELSE without matching $IF")
+			return f.hide(tok)
+		}
+		f.closeBranch(tok)
+		f.openBranch(tok, "", !f.frames[len(f.frames)-1].taken, tok)
+		return f.hide(tok)
+	case gen.PlSqlLexerCC_END:
+		if len(f.frames) == 0 {
+			f.errorf(tok, "$END without matching $IF")
+			return f.hide(tok)
+		}
+		f.closeBranch(tok)
+		f.frames = f.frames[:len(f.frames)-1]
+		return f.hide(tok)
+	case gen.PlSqlLexerCC_THEN:
+		f.errorf(tok, "$THEN without matching $IF")
+		return f.hide(tok)
+	case gen.PlSqlLexerCC_ERROR:
+		return f.errorDirective(tok)
+	case gen.PlSqlLexerINQUIRY_DIRECTIVE:
+		if f.live() {
+			return f.retype(tok, gen.PlSqlLexerREGULAR_ID, tok.GetChannel())
+		}
+		return f.hide(tok)
+	case antlr.TokenEOF:
+		for len(f.frames) > 0 {
+			f.closeBranch(tok)
+			open := f.branches[f.frames[len(f.frames)-1].branch]
+			f.errorAt(open.Line, open.Column, "$IF without matching $END")
+			f.frames = f.frames[:len(f.frames)-1]
+		}
+		return tok
+	}
+
+	if !f.live() && tok.GetChannel() == antlr.TokenDefaultChannel {
+		return f.hide(tok)
+	}
+	return tok
+}
+
+// conditional handles $IF and $ELSIF, reading their condition up to $THEN
+func (f *directiveFilter) conditional(directive antlr.Token) antlr.Token {
+	condition, then := f.readUntil(gen.PlSqlLexerCC_THEN)
+	if then == nil {
+		f.errorf(directive, "%s without matching $THEN", strings.ToUpper(directive.GetText()))
+	}
+
+	if directive.GetTokenType() == gen.PlSqlLexerCC_IF {
+		f.frames = append(f.frames, ccFrame{parent: f.live()})
+	} else if len(f.frames) == 0 {
+		f.errorf(directive, "$ELSIF without matching $IF")
+		return f.hide(directive)
+	} else {
+		f.closeBranch(directive)
+	}
+
+	selected := false
+	if !f.frames[len(f.frames)-1].taken {
+		selected = f.evaluate(directive, condition)
+	}
+	if then == nil {
+		then = directive
+	}
+	f.openBranch(directive, f.text(condition), selected, then)
+	return f.hide(directive)
+}
+
+// errorDirective handles $ERROR, whose message extends up to $END
+func (f *directiveFilter) errorDirective(directive antlr.Token) antlr.Token {
+	message, end := f.readUntil(gen.PlSqlLexerCC_END)
+	if end == nil {
+		f.errorf(directive, "$ERROR without matching $END")
+	}
+	if f.evaluating() && f.live() {
+		f.errorf(directive, "$ERROR: %s", f.message(message))
+	}
+	return f.hide(directive)
+}
+
+// readUntil queues the hidden tokens up to and including the first token of
+// the given type, and returns the default channel tokens before it together
+// with that token. The token is nil when the script ends first.
+func (f *directiveFilter) readUntil(tokenType int) ([]antlr.Token, antlr.Token) {
+	var tokens []antlr.Token
+	for {
+		tok := f.next()
+		switch tok.GetTokenType() {
+		case tokenType:
+			f.queue = append(f.queue, f.hide(tok))
+			return tokens, tok
+		case antlr.TokenEOF:
+			f.lookahead = tok
+			return tokens, nil
+		}
+		if tok.GetChannel() == antlr.TokenDefaultChannel {
+			tokens = append(tokens, tok)
+		}
+		f.queue = append(f.queue, f.hide(tok))
+	}
+}
+
+// openBranch starts a branch of the innermost $IF after the token at
+func (f *directiveFilter) openBranch(directive antlr.Token, condition string, selected bool, at antlr.Token) {
+	frame := &f.frames[len(f.frames)-1]
+	frame.live = frame.parent && selected
+	frame.taken = frame.taken || selected
+	frame.branch = len(f.branches)
+
+	f.branches = append(f.branches, ccBranch{
+		Branch: Branch{
+			Directive: strings.ToUpper(directive.GetText()),
+			Condition: condition,
+			Line:      directive.GetLine(),
+			Column:    directive.GetColumn(),
+			Depth:     len(f.frames) - 1,
+			Live:      frame.live,
+		},
+		start: at.GetStop() + 1,
+		stop:  at.GetStop() + 1,
+	})
+}
+
+// closeBranch ends the current branch of the innermost $IF before the token at
+func (f *directiveFilter) closeBranch(at antlr.Token) {
+	frame := f.frames[len(f.frames)-1]
+	if frame.branch < len(f.branches) && f.branches[frame.branch].start <= at.GetStart() {
+		f.branches[frame.branch].stop = at.GetStart()
+	}
+}
+
+// hide returns tok on the hidden channel
+func (f *directiveFilter) hide(tok antlr.Token) antlr.Token {
+	if tok.GetChannel() == antlr.TokenHiddenChannel {
+		return tok
+	}
+	return f.retype(tok, tok.GetTokenType(), antlr.TokenHiddenChannel)
+}
+
+// retype returns a copy of tok with another type and channel
+func (f *directiveFilter) retype(tok antlr.Token, tokenType, channel int) antlr.Token {
+	return f.GetTokenFactory().Create(tok.GetSource(), tokenType, tok.GetText(), channel,
+		tok.GetStart(), tok.GetStop(), tok.GetLine(), tok.GetColumn())
+}
+
+// text returns the script text from the first to the last of tokens
+func (f *directiveFilter) text(tokens []antlr.Token) string {
+	if len(tokens) == 0 {
+		return ""
+	}
+	return f.GetInputStream().GetText(tokens[0].GetStart(), tokens[len(tokens)-1].GetStop())
+}
+
+// message returns the text of an $ERROR directive. A single string literal is
+// unquoted, anything else is reported as written.
+func (f *directiveFilter) message(tokens []antlr.Token) string {
+	if len(tokens) == 1 && tokens[0].GetTokenType() == gen.PlSqlLexerCHAR_STRING {
+		return unquote(tokens[0].GetText())
+	}
+	return f.text(tokens)
+}
+
+// errorf records a directive error at tok
+func (f *directiveFilter) errorf(tok antlr.Token, format string, args ...any) {
+	f.errorAt(tok.GetLine(), tok.GetColumn(), format, args...)
+}
+
+// errorAt records a directive error at a position
+func (f *directiveFilter) errorAt(line, column int, format string, args ...any) {
+	f.errors = append(f.errors, ccError{line: line, column: column, msg: fmt.Sprintf(format, args...)})
+}
+
+// report passes the directive errors to an error listener
+func (f *directiveFilter) report(listener *CustomErrorListener) {
+	for _, e := range f.errors {
+		listener.SyntaxError(nil, nil, e.line, e.column, e.msg, nil)
+	}
+}
+
+// result returns the branches with byte offsets
+func (f *directiveFilter) result(index *source.Index) []Branch {
+	branches := make([]Branch, len(f.branches))
+	for i, b := range f.branches {
+		branches[i] = b.Branch
+		branches[i].StartOffset = index.ByteOffset(b.start)
+		branches[i].EndOffset = index.ByteOffset(b.stop)
+	}
+	return branches
+}
+
+// Branches resolves the conditional compilation directives of a script with
+// the CCFlags and Version of opts, and returns every $IF, $ELSIF and $ELSE
+// branch together with the directive errors. The script is not parsed.
+func Branches(input string, opts ParseOptions) ([]Branch, []SyntaxError) {
+	index := source.NewIndex(input)
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	lexer.RemoveErrorListeners()
+
+	f := newDirectiveFilter(lexer, opts.CCFlags, opts.Version)
+	for f.NextToken().GetTokenType() != antlr.TokenEOF {
+	}
+
+	errorListener := NewCustomErrorListener(opts.MaxErrors, input, opts.ContextLines)
+	f.report(errorListener)
+	return f.result(index), errorListener.Errors
+}
+
+// evaluate reports whether the condition of a $IF or $ELSIF selects its
+// branch. Without flags only $IF branches are selected.
+func (f *directiveFilter) evaluate(directive antlr.Token, condition []antlr.Token) bool {
+	if !f.evaluating() {
+		return directive.GetTokenType() == gen.PlSqlLexerCC_IF
+	}
+
+	// Conditions in code that is not compiled are not evaluated
+	if !f.frames[len(f.frames)-1].parent {
+		return false
+	}
+
+	e := &ccExpression{tokens: condition, lookup: f.value}
+	value, err := e.parse()
+	if err == nil {
+		if _, ok := value.(bool); !ok && value != nil {
+			err = fmt.Errorf("%s is not a BOOLEAN", typeName(value))
+		}
+	}
+	if err != nil {
+		f.errorf(directive, "invalid %s condition: %v", strings.ToUpper(directive.GetText()), err)
+		return false
+	}
+	return value == true
+}
+
+// value returns the value of an inquiry directive such as $$debug or of a
+// static constant such as dbms_db_version.ver_le_19. Flags take precedence
+// over the predefined values, and names that are not defined are NULL, as
+// they are for the PL/SQL preprocessor.
+func (f *directiveFilter) value(name string, at antlr.Token) any {
+	name = strings.ToLower(name)
+	directive, isDirective := strings.CutPrefix(name, "$$")
+	if value, ok := f.flags[directive]; ok {
+		return value
+	}
+	if !isDirective {
+		return f.version.dbmsDBVersion(name)
+	}
+
+	switch directive {
+	case "plsql_line":
+		return at.GetLine()
+	case "plsql_code_type":
+		return "INTERPRETED"
+	case "plsql_optimize_level":
+		return 2
+	case "nls_length_semantics":
+		return "BYTE"
+	}
+	return nil
+}
+
+// ccExpression is a static expression of a conditional compilation
+// directive. Its values are bool, int, string or nil for NULL.
+type ccExpression struct {
+	tokens []antlr.Token
+	pos    int
+	lookup func(name string, at antlr.Token) any
+}
+
+// parse evaluates the whole expression
+func (e *ccExpression) parse() (any, error) {
+	if len(e.tokens) == 0 {
+		return nil, fmt.Errorf("missing condition")
+	}
+	value, err := e.or()
+	if err == nil && e.pos < len(e.tokens) {
+		err = fmt.Errorf("unexpected %q", e.tokens[e.pos].GetText())
+	}
+	return value, err
+}
+
+// peek returns the type of the next token, or EOF at the end
+func (e *ccExpression) peek() int {
+	if e.pos < len(e.tokens) {
+		return e.tokens[e.pos].GetTokenType()
+	}
+	return antlr.TokenEOF
+}
+
+// accept consumes the next token if it has the given type
+func (e *ccExpression) accept(tokenType int) bool {
+	if e.peek() != tokenType {
+		return false
+	}
+	e.pos++
+	return true
+}
+
+func (e *ccExpression) or() (any, error) {
+	left, err := e.and()
+	for err == nil && e.accept(gen.PlSqlLexerOR) {
+		var right any
+		if right, err = e.and(); err == nil {
+			left, err = logical(left, right, true)
+		}
+	}
+	return left, err
+}
+
+func (e *ccExpression) and() (any, error) {
+	left, err := e.not()
+	for err == nil && e.accept(gen.PlSqlLexerAND) {
+		var right any
+		if right, err = e.not(); err == nil {
+			left, err = logical(left, right, false)
+		}
+	}
+	return left, err
+}
+
+func (e *ccExpression) not() (any, error) {
+	if !e.accept(gen.PlSqlLexerNOT) {
+		return e.comparison()
+	}
+	value, err := e.not()
+	if err != nil || value == nil {
+		return nil, err
+	}
+	b, ok := value.(bool)
+	if !ok {
+		return nil, fmt.Errorf("NOT applied to a %s", typeName(value))
+	}
+	return !b, nil
+}
+
+func (e *ccExpression) comparison() (any, error) {
+	left, err := e.primary()
+	if err != nil {
+		return nil, err
+	}
+
+	if e.accept(gen.PlSqlLexerIS) {
+		negate := e.accept(gen.PlSqlLexerNOT)
+		if !e.accept(gen.PlSqlLexerNULL_) {
+			return nil, fmt.Errorf("expected NULL after IS")
+		}
+		return (left == nil) != negate, nil
+	}
+
+	var op string
+	switch {
+	case e.accept(gen.PlSqlLexerEQUALS_OP):
+		op = "="
+	case e.accept(gen.PlSqlLexerNOT_EQUAL_OP):
+		op = "!="
+	case e.accept(gen.PlSqlLexerLESS_THAN_OP):
+		op = "<"
+	case e.accept(gen.PlSqlLexerGREATER_THAN_OP):
+		op = ">"
+	default:
+		return left, nil
+	}
+	if op != "=" && op != "!=" && e.accept(gen.PlSqlLexerEQUALS_OP) {
+		op += "="
+	}
+
+	right, err := e.primary()
+	if err != nil {
+		return nil, err
+	}
+	return compare(left, op, right)
+}
+
+func (e *ccExpression) primary() (any, error) {
+	if e.pos >= len(e.tokens) {
+		return nil, fmt.Errorf("unexpected end of condition")
+	}
+	tok := e.tokens[e.pos]
+	e.pos++
+
+	switch tok.GetTokenType() {
+	case gen.PlSqlLexerLEFT_PAREN:
+		value, err := e.or()
+		if err == nil && !e.accept(gen.PlSqlLexerRIGHT_PAREN) {
+			err = fmt.Errorf("missing )")
+		}
+		return value, err
+	case gen.PlSqlLexerTRUE:
+		return true, nil
+	case gen.PlSqlLexerFALSE:
+		return false, nil
+	case gen.PlSqlLexerNULL_:
+		return nil, nil
+	case gen.PlSqlLexerUNSIGNED_INTEGER:
+		return strconv.Atoi(tok.GetText())
+	case gen.PlSqlLexerMINUS_SIGN:
+		value, err := e.primary()
+		if err != nil || value == nil {
+			return nil, err
+		}
+		n, ok := value.(int)
+		if !ok {
+			return nil, fmt.Errorf("- applied to a %s", typeName(value))
+		}
+		return -n, nil
+	case gen.PlSqlLexerCHAR_STRING:
+		return unquote(tok.GetText()), nil
+	case gen.PlSqlLexerINQUIRY_DIRECTIVE:
+		return e.lookup(tok.GetText(), tok), nil
+	case gen.PlSqlLexerAND, gen.PlSqlLexerOR, gen.PlSqlLexerNOT, gen.PlSqlLexerIS:
+		return nil, fmt.Errorf("unexpected %q", tok.GetText())
+	}
+
+	// Static constants are names qualified by their package
+	if !isName(tok.GetText()) {
+		return nil, fmt.Errorf("unexpected %q", tok.GetText())
+	}
+	name := tok.GetText()
+	for e.accept(gen.PlSqlLexerPERIOD) {
+		if e.pos >= len(e.tokens) || !isName(e.tokens[e.pos].GetText()) {
+			return nil, fmt.Errorf("expected a name after %q", name+".")
+		}
+		name += "." + e.tokens[e.pos].GetText()
+		e.pos++
+	}
+	return e.lookup(name, tok), nil
+}
+
+// logical applies AND or OR with three-valued logic
+func logical(left, right any, or bool) (any, error) {
+	for _, value := range []any{left, right} {
+		if _, ok := value.(bool); !ok && value != nil {
+			return nil, fmt.Errorf("%s operand of a logical operator", typeName(value))
+		}
+	}
+	switch {
+	case left == or || right == or:
+		return or, nil
+	case left == nil || right == nil:
+		return nil, nil
+	}
+	return !or, nil
+}
+
+// compare applies a comparison operator. Comparisons with NULL are NULL.
+func compare(left any, op string, right any) (any, error) {
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	var order int
+	switch l := left.(type) {
+	case int:
+		r, ok := right.(int)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare a %s with a %s", typeName(left), typeName(right))
+		}
+		order = l - r
+	case string:
+		r, ok := right.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot compare a %s with a %s", typeName(left), typeName(right))
+		}
+		order = strings.Compare(l, r)
+	case bool:
+		r, ok := right.(bool)
+		if !ok || (op != "=" && op != "!=") {
+			return nil, fmt.Errorf("cannot compare a %s with a %s using %s", typeName(left), typeName(right), op)
+		}
+		if l != r {
+			order = 1
+		}
+	default:
+		return nil, fmt.Errorf("unsupported %s value", typeName(left))
+	}
+
+	switch op {
+	case "=":
+		return order == 0, nil
+	case "!=":
+		return order != 0, nil
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+// typeName returns the PL/SQL type of a directive value
+func typeName(value any) string {
+	switch value.(type) {
+	case bool:
+		return "BOOLEAN"
+	case int:
+		return "PLS_INTEGER"
+	case string:
+		return "VARCHAR2"
+	case nil:
+		return "NULL"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// isName reports whether text is an unquoted identifier
+func isName(text string) bool {
+	for i, r := range text {
+		isLetter := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		if !isLetter && (i == 0 || !(r >= '0' && r <= '9') && r != '_' && r != '$' && r != '#') {
+			return false
+		}
+	}
+	return text != ""
+}
+
+// unquote returns the value of a character string literal
+func unquote(literal string) string {
+	if len(literal) >= 2 && literal[0] == '\'' && literal[len(literal)-1] == '\'' {
+		literal = literal[1 : len(literal)-1]
+	}
+	return strings.ReplaceAll(literal, "''", "'")
+}
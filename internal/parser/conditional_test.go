@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+)
+
+const conditionalScript = `CREATE OR REPLACE PACKAGE BODY p AS
+  PROCEDURE run IS
+  BEGIN
+    $IF $$debug $THEN
+      trace($$PLSQL_UNIT);
+    $ELSIF DBMS_DB_VERSION.VER_LE_12_1 AND NOT $$quiet $THEN
+      old_path;
+    $ELSE
+      $IF $$level > 2 $THEN deep; $END
+      normal;
+    $END
+  END run;
+END p;
+/
+SELECT 1 FROM dual;
+`
+
+func TestBranches_Live(t *testing.T) {
+	tests := []struct {
+		name    string
+		flags   map[string]any
+		version Version
+		live    string
+	}{
+		{"no flags", nil, VersionDefault, "1000"},
+		{"debug", map[string]any{"debug": true}, VersionDefault, "1000"},
+		{"empty flags", map[string]any{}, VersionDefault, "0010"},
+		{"12c with NULL quiet", map[string]any{}, Version12c, "0010"},
+		{"12c", map[string]any{"quiet": false}, Version12c, "0100"},
+		{"level", map[string]any{"level": 3}, VersionDefault, "0011"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			branches, errs := Branches(conditionalScript, ParseOptions{CCFlags: tt.flags, Version: tt.version})
+			if len(errs) > 0 {
+				t.Fatalf("Unexpected errors: %v", errs)
+			}
+
+			var live strings.Builder
+			for _, b := range branches {
+				if b.Live {
+					live.WriteByte('1')
+				} else {
+					live.WriteByte('0')
+				}
+			}
+			if live.String() != tt.live {
+				t.Errorf("Expected live branches %s, got %s", tt.live, live.String())
+			}
+		})
+	}
+}
+
+func TestBranches_Positions(t *testing.T) {
+	branches, _ := Branches(conditionalScript, ParseOptions{})
+	if len(branches) != 4 {
+		t.Fatalf("Expected 4 branches, got %d", len(branches))
+	}
+
+	elsif := branches[1]
+	if elsif.Directive != "$ELSIF" || elsif.Condition != "DBMS_DB_VERSION.VER_LE_12_1 AND NOT $$quiet" {
+		t.Errorf("Unexpected $ELSIF branch %+v", elsif)
+	}
+	if elsif.Line != 6 || elsif.Column != 4 {
+		t.Errorf("Expected the $ELSIF at 6:4, got %d:%d", elsif.Line, elsif.Column)
+	}
+	if code := strings.TrimSpace(conditionalScript[elsif.StartOffset:elsif.EndOffset]); code != "old_path;" {
+		t.Errorf("Expected the $ELSIF code, got %q", code)
+	}
+
+	nested := branches[3]
+	if nested.Depth != 1 || nested.Condition != "$$level > 2" {
+		t.Errorf("Unexpected nested branch %+v", nested)
+	}
+}
+
+func TestBranches_Errors(t *testing.T) {
+	flags := map[string]any{"a": "x", "b": -2}
+	tests := []struct {
+		script string
+		err    string
+	}{
+		{"BEGIN $IF $$a = 'x' OR $$b < -1 $THEN NULL; $END END;", ""},
+		{"BEGIN $IF $$a IS NOT NULL $THEN NULL; $END END;", ""},
+		{"BEGIN $IF FALSE $THEN $ERROR 'no' $END $END END;", ""},
+		{"BEGIN $IF $$x $THEN NULL; END;", "$IF without matching $END"},
+		{"BEGIN NULL; $END END;", "$END without matching $IF"},
+		{"BEGIN $IF 1 $THEN NULL; $END END;", "invalid $IF condition: PLS_INTEGER is not a BOOLEAN"},
+		{"BEGIN $IF ($$a $THEN NULL; $END END;", "invalid $IF condition: missing )"},
+		{"BEGIN $IF TRUE = 1 $THEN NULL; $END END;", "invalid $IF condition: cannot compare a BOOLEAN with a PLS_INTEGER using ="},
+		{"BEGIN $IF TRUE $THEN $ERROR 'it''s' $END $END END;", "$ERROR: it's"},
+		{"BEGIN $IF TRUE $THEN $ERROR 'nope ' || $$PLSQL_UNIT $END $END END;", "$ERROR: 'nope ' || $$PLSQL_UNIT"},
+	}
+
+	for _, tt := range tests {
+		_, errs := Branches(tt.script, ParseOptions{CCFlags: flags})
+		got := ""
+		if len(errs) > 0 {
+			got = errs[0].Message
+		}
+		if got != tt.err {
+			t.Errorf("%s: expected error %q, got %q", tt.script, tt.err, got)
+		}
+	}
+}
+
+func TestSplitLexical_ConditionalCompilation(t *testing.T) {
+	statements := SplitLexical(conditionalScript)
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+	if !strings.HasSuffix(statements[0].Content, "END p;") {
+		t.Errorf("Expected the package body as one statement, got %q", statements[0].Content)
+	}
+}
@@ -0,0 +1,322 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Codes of the syntax errors that are recognized and explained in plain
+// language. Other errors keep the parser's message and have no code.
+// Unterminated strings and comments and invalid characters are lexical
+// errors.
+const (
+	CodeMissingEnd          = "PLS-SPLIT-0001" // A block is not closed by an END
+	CodeUnmatchedEnd        = "PLS-SPLIT-0002" // An END closes no block
+	CodeMissingSlash        = "PLS-SPLIT-0003" // A PL/SQL unit is not followed by a / line
+	CodeUnterminatedString  = "PLS-SPLIT-0004" // A string literal is not closed
+	CodeUnterminatedComment = "PLS-SPLIT-0005" // A /* comment is not closed
+	CodeMissingComma        = "PLS-SPLIT-0006" // Two list items are not separated by a comma
+	CodeReservedWord        = "PLS-SPLIT-0007" // A reserved word is used as an identifier
+	CodeMissingSemicolon    = "PLS-SPLIT-0008" // A statement is not terminated
+	CodeInvalidCharacter    = "PLS-SPLIT-0009" // A character cannot start any token
+)
+
+// explainErrors attributes the errors of a parse to their statements and
+// rewrites the errors it recognizes in plain language. tokens are all the
+// tokens the parser read, on every channel.
+func explainErrors(errorListener *CustomErrorListener, tokens []antlr.Token, index *source.Index, settings Terminators) {
+	syntaxErrors := errorListener.Errors
+	lexical := attributeErrors(syntaxErrors, index.Text(), settings)
+
+	d := &diagnoser{index: index, lexical: lexical}
+	for _, tok := range tokens {
+		if tok.GetChannel() == antlr.TokenDefaultChannel {
+			d.tokens = append(d.tokens, tok)
+		}
+	}
+
+	for i := range syntaxErrors {
+		if !syntaxErrors[i].parser {
+			continue
+		}
+		line, column := syntaxErrors[i].Line, syntaxErrors[i].Column
+		d.explain(&syntaxErrors[i])
+
+		// The context follows the error to its likely cause
+		moved := syntaxErrors[i].Line != line || syntaxErrors[i].Column != column
+		if moved && syntaxErrors[i].Context != "" {
+			syntaxErrors[i].Context = errorListener.extractErrorContext(syntaxErrors[i].Line, syntaxErrors[i].Column)
+		}
+	}
+}
+
+// diagnoser recognizes common causes of syntax errors
+type diagnoser struct {
+	index   *source.Index
+	tokens  []antlr.Token // Default channel tokens, ending with EOF
+	lexical []Statement   // Statements found lexically
+}
+
+// openBlock is a block that an END has yet to close
+type openBlock struct {
+	tok         antlr.Token // BEGIN, DECLARE, CASE, IS or AS that opened the block
+	awaitsBegin bool        // DECLARE and IS/AS blocks are closed by the END of the BEGIN that follows them
+}
+
+// explain rewrites err when its cause is recognized. Checks run from the most
+// to the least specific cause.
+func (d *diagnoser) explain(err *SyntaxError) {
+	at := d.tokenAt(err)
+	if at < 0 {
+		return
+	}
+
+	checks := []func(*SyntaxError, int) bool{
+		d.explainBlocks,
+		d.explainMissingSlash,
+		d.explainMissingSemicolon,
+		d.explainReservedWord,
+		d.explainMissingComma,
+	}
+	for _, check := range checks {
+		if check(err, at) {
+			return
+		}
+	}
+}
+
+// explainBlocks reports an END that closes no block, or a unit that ends while
+// a block is still open
+func (d *diagnoser) explainBlocks(err *SyntaxError, at int) bool {
+	from := d.statementStart(err)
+	open, unmatched := d.scanBlocks(from, at+1)
+	if unmatched != nil {
+		d.rewrite(err, CodeUnmatchedEnd, d.offset(unmatched), "END without a matching BEGIN")
+		return true
+	}
+	if len(open) == 0 || !d.endsUnit(at) {
+		return false
+	}
+
+	opener := open[len(open)-1].tok
+	d.rewrite(err, CodeMissingEnd, d.offset(opener),
+		fmt.Sprintf("missing END for the %s on line %d", strings.ToUpper(opener.GetText()), opener.GetLine()))
+	return true
+}
+
+// explainMissingSlash reports a statement that follows a PL/SQL unit without
+// the / line that SQL*Plus needs to run the unit
+func (d *diagnoser) explainMissingSlash(err *SyntaxError, at int) bool {
+	k := err.StatementIndex
+	if k <= 0 || d.lexical[k].StartOffset != d.offset(d.tokens[at]) {
+		return false
+	}
+	if !isPLSQLType(d.lexical[k-1].Type) {
+		return false
+	}
+
+	// The previous statement must have ended on its own, not at a slash line
+	between := d.index.Slice(d.lexical[k-1].EndOffset, d.lexical[k].StartOffset)
+	for _, line := range strings.Split(between, "\n") {
+		if strings.TrimSpace(line) == "/" {
+			return false
+		}
+	}
+
+	d.rewrite(err, CodeMissingSlash, d.offset(d.tokens[at]),
+		fmt.Sprintf("missing / after the PL/SQL unit ending on line %d", d.lexical[k-1].EndLine))
+	return true
+}
+
+// explainMissingSemicolon reports a statement that runs into the next line
+// where the parser expected it to end
+func (d *diagnoser) explainMissingSemicolon(err *SyntaxError, at int) bool {
+	if at == 0 || !slices.Contains(err.Expected, ";") {
+		return false
+	}
+	tok, prev := d.tokens[at], d.tokens[at-1]
+	if prev.GetLine() == tok.GetLine() || prev.GetTokenType() == gen.PlSqlLexerSEMICOLON || prev.GetTokenType() == gen.PlSqlLexerSOLIDUS {
+		return false
+	}
+
+	// The terminator belongs just after the previous token
+	end := d.index.ByteOffset(prev.GetStop() + 1)
+	d.rewrite(err, CodeMissingSemicolon, end, fmt.Sprintf("missing ; at the end of line %d", prev.GetLine()))
+	return true
+}
+
+// explainReservedWord reports a reserved word where the parser expected an
+// identifier
+func (d *diagnoser) explainReservedWord(err *SyntaxError, at int) bool {
+	tok := d.tokens[at]
+	word := strings.ToUpper(tok.GetText())
+	if !gen.ReservedWords[word] || !slices.Contains(err.Expected, "REGULAR_ID") {
+		return false
+	}
+
+	d.rewrite(err, CodeReservedWord, d.offset(tok),
+		fmt.Sprintf("%s is a reserved word and cannot be used as an identifier unless it is quoted, as in \"%s\"", word, word))
+	return true
+}
+
+// explainMissingComma reports two adjacent list items where the parser
+// expected a comma between them
+func (d *diagnoser) explainMissingComma(err *SyntaxError, at int) bool {
+	if at == 0 || !slices.Contains(err.Expected, ",") {
+		return false
+	}
+	tok, prev := d.tokens[at], d.tokens[at-1]
+	if !isListItem(tok) || (!isListItem(prev) && prev.GetTokenType() != gen.PlSqlLexerRIGHT_PAREN) {
+		return false
+	}
+
+	d.rewrite(err, CodeMissingComma, d.offset(tok), fmt.Sprintf("missing comma before %s", tok.GetText()))
+	return true
+}
+
+// scanBlocks follows the blocks opened and closed by the tokens in [from, to).
+// It returns the blocks left open, or the first END that closes no block.
+// Blocks are tracked like the lexical splitter does.
+func (d *diagnoser) scanBlocks(from, to int) ([]openBlock, antlr.Token) {
+	var open []openBlock
+	inHeader := false // Between a subprogram name and its AS/IS
+	parens := 0
+
+	for j := from; j < to; j++ {
+		tok := d.tokens[j]
+		switch tok.GetTokenType() {
+		case gen.PlSqlLexerLEFT_PAREN:
+			parens++
+		case gen.PlSqlLexerRIGHT_PAREN:
+			parens--
+		case gen.PlSqlLexerSEMICOLON:
+			inHeader = false
+		case gen.PlSqlLexerPROCEDURE, gen.PlSqlLexerFUNCTION, gen.PlSqlLexerPACKAGE:
+			inHeader = true
+		case gen.PlSqlLexerTYPE:
+			inHeader = inHeader || d.is(j+1, gen.PlSqlLexerBODY)
+		case gen.PlSqlLexerIS, gen.PlSqlLexerAS:
+			if inHeader && parens == 0 {
+				inHeader = false
+				// Call specifications have no body
+				if !d.is(j+1, gen.PlSqlLexerLANGUAGE) && !d.is(j+1, gen.PlSqlLexerEXTERNAL) {
+					open = append(open, openBlock{tok: tok, awaitsBegin: true})
+				}
+			}
+		case gen.PlSqlLexerDECLARE:
+			open = append(open, openBlock{tok: tok, awaitsBegin: true})
+		case gen.PlSqlLexerBEGIN:
+			if n := len(open); n > 0 && open[n-1].awaitsBegin {
+				open[n-1] = openBlock{tok: tok}
+			} else {
+				open = append(open, openBlock{tok: tok})
+			}
+		case gen.PlSqlLexerCASE:
+			if !d.is(j-1, gen.PlSqlLexerEND) {
+				open = append(open, openBlock{tok: tok})
+			}
+		case gen.PlSqlLexerCOMPOUND:
+			if d.is(j+1, gen.PlSqlLexerTRIGGER) {
+				open = append(open, openBlock{tok: tok})
+			}
+		case gen.PlSqlLexerEND:
+			// END IF and END LOOP close statements that open no block
+			if d.is(j+1, gen.PlSqlLexerIF) || d.is(j+1, gen.PlSqlLexerLOOP) {
+				continue
+			}
+			if len(open) == 0 {
+				return nil, tok
+			}
+			open = open[:len(open)-1]
+		}
+	}
+	return open, nil
+}
+
+// endsUnit reports whether token i ends the unit it is in: the end of the
+// script, a slash line or a CREATE at the start of a line
+func (d *diagnoser) endsUnit(i int) bool {
+	tok := d.tokens[i]
+	start := d.offset(tok)
+	switch tok.GetTokenType() {
+	case antlr.TokenEOF:
+		return true
+	case gen.PlSqlLexerSOLIDUS:
+		return d.index.AloneOnLine(start, d.index.ByteOffset(tok.GetStop()+1))
+	case gen.PlSqlLexerCREATE:
+		text := d.index.Text()
+		lineStart := strings.LastIndexByte(text[:start], '\n') + 1
+		return strings.TrimSpace(text[lineStart:start]) == ""
+	}
+	return false
+}
+
+// statementStart returns the index of the first token of the statement that
+// owns err
+func (d *diagnoser) statementStart(err *SyntaxError) int {
+	if err.StatementIndex < 0 {
+		return 0
+	}
+	start := d.lexical[err.StatementIndex].StartOffset
+	return sort.Search(len(d.tokens), func(i int) bool { return d.offset(d.tokens[i]) >= start })
+}
+
+// tokenAt returns the index of the offending token of err, or -1
+func (d *diagnoser) tokenAt(err *SyntaxError) int {
+	if err.Token == nil {
+		return -1
+	}
+	i := sort.Search(len(d.tokens), func(i int) bool { return d.offset(d.tokens[i]) >= err.Token.StartOffset })
+	if i == len(d.tokens) {
+		return -1
+	}
+	return i
+}
+
+// rewrite replaces the message of err and moves it to a byte offset. The
+// parser's own message is kept in ParserMessage.
+func (d *diagnoser) rewrite(err *SyntaxError, code string, offset int, message string) {
+	err.ParserMessage = err.Message
+	err.Message = message
+	err.Code = code
+	err.Line, err.Column = d.index.LineColumn(offset)
+}
+
+// offset returns the byte offset of a token
+func (d *diagnoser) offset(tok antlr.Token) int {
+	return d.index.ByteOffset(tok.GetStart())
+}
+
+// is reports whether token i exists and has the given type
+func (d *diagnoser) is(i int, tokenType int) bool {
+	return i >= 0 && i < len(d.tokens) && d.tokens[i].GetTokenType() == tokenType
+}
+
+// isListItem reports whether a token can be a whole item of a column or
+// value list: a name that is not reserved or a literal
+func isListItem(tok antlr.Token) bool {
+	switch tok.GetTokenType() {
+	case gen.PlSqlLexerDELIMITED_ID, gen.PlSqlLexerCHAR_STRING,
+		gen.PlSqlLexerUNSIGNED_INTEGER, gen.PlSqlLexerAPPROXIMATE_NUM_LIT:
+		return true
+	}
+	text := tok.GetText()
+	return isName(text) && !gen.ReservedWords[strings.ToUpper(text)]
+}
+
+// isPLSQLType reports whether a statement type is a PL/SQL unit that SQL*Plus
+// only runs at a / line
+func isPLSQLType(statementType string) bool {
+	switch statementType {
+	case "PLSQL_BLOCK", "CREATE_PROCEDURE", "CREATE_FUNCTION", "CREATE_PACKAGE",
+		"CREATE_PACKAGE_BODY", "CREATE_TRIGGER", "CREATE_TYPE", "CREATE_TYPE_BODY":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// explainAt reports a parser error at the first token that starts at marker
+// in input, with the given expected tokens, and explains it
+func explainAt(t *testing.T, input, marker string, expected ...string) SyntaxError {
+	t.Helper()
+	index := source.NewIndex(input)
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	lexer.RemoveErrorListeners()
+	filter := newTerminatorFilter(newDirectiveFilter(lexer, nil, VersionDefault), index, Terminators{})
+
+	var tokens []antlr.Token
+	for {
+		tok := filter.NextToken()
+		tokens = append(tokens, tok)
+		if tok.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+	}
+
+	offset := len(input)
+	if marker != "" {
+		offset = strings.Index(input, marker)
+	}
+	var at antlr.Token
+	for _, tok := range tokens {
+		if tok.GetChannel() == antlr.TokenDefaultChannel && index.ByteOffset(tok.GetStart()) == offset {
+			at = tok
+			break
+		}
+	}
+	if at == nil {
+		t.Fatalf("No token at %q", marker)
+	}
+
+	listener := NewCustomErrorListener(10, input, 1)
+	listener.Errors = []SyntaxError{{
+		Line:           at.GetLine(),
+		Column:         at.GetColumn(),
+		Message:        "mismatched input",
+		Token:          &ErrorToken{Text: at.GetText(), StartOffset: offset},
+		Expected:       expected,
+		StatementIndex: -1,
+		parser:         true,
+	}}
+	explainErrors(listener, tokens, index, Terminators{})
+	return listener.Errors[0]
+}
+
+func TestExplainErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		marker   string // Offending token, or "" for EOF
+		expected []string
+		code     string
+		message  string
+		line     int
+		column   int
+	}{
+		{
+			name:    "missing END",
+			input:   "BEGIN\n  IF x THEN\n    NULL;\n  END IF;\n",
+			code:    CodeMissingEnd,
+			message: "missing END for the BEGIN on line 1",
+			line:    1,
+		},
+		{
+			name:    "missing END of a nested block",
+			input:   "CREATE PROCEDURE p IS\nBEGIN\n  BEGIN\n    NULL;\nEND;\n/\n",
+			marker:  "/",
+			code:    CodeMissingEnd,
+			message: "missing END for the BEGIN on line 2",
+			line:    2,
+		},
+		{
+			name:    "unmatched END",
+			input:   "BEGIN\n  NULL;\nEND;\nEND p;\n",
+			marker:  "END p",
+			code:    CodeUnmatchedEnd,
+			message: "END without a matching BEGIN",
+			line:    4,
+		},
+		{
+			name:     "missing slash",
+			input:    "CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\nSELECT * FROM dual;\n",
+			marker:   "SELECT",
+			code:     CodeMissingSlash,
+			message:  "missing / after the PL/SQL unit ending on line 4",
+			line:     5,
+			expected: []string{"EOF"},
+		},
+		{
+			name:     "missing comma",
+			input:    "SELECT emp_id name FROM employees;\nINSERT INTO t (a b) VALUES (1, 2);\n",
+			marker:   "b)",
+			expected: []string{")", ","},
+			code:     CodeMissingComma,
+			message:  "missing comma before b",
+			line:     2,
+			column:   17,
+		},
+		{
+			name:     "reserved word",
+			input:    "CREATE TABLE t (id NUMBER, table VARCHAR2(10));\n",
+			marker:   "table",
+			expected: []string{"REGULAR_ID", "DELIMITED_ID"},
+			code:     CodeReservedWord,
+			message:  "TABLE is a reserved word and cannot be used as an identifier unless it is quoted, as in \"TABLE\"",
+			line:     1,
+			column:   27,
+		},
+		{
+			name:     "missing semicolon",
+			input:    "BEGIN\n  x := 1\n  y := 2;\nEND;\n",
+			marker:   "y",
+			expected: []string{";", "+"},
+			code:     CodeMissingSemicolon,
+			message:  "missing ; at the end of line 2",
+			line:     2,
+			column:   8,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := explainAt(t, tc.input, tc.marker, tc.expected...)
+			if err.Code != tc.code || err.Message != tc.message {
+				t.Fatalf("Expected %s %q, got %s %q", tc.code, tc.message, err.Code, err.Message)
+			}
+			if err.Line != tc.line || err.Column != tc.column {
+				t.Errorf("Expected the error at %d:%d, got %d:%d", tc.line, tc.column, err.Line, err.Column)
+			}
+			if err.ParserMessage != "mismatched input" {
+				t.Errorf("Expected the parser message to be kept, got %q", err.ParserMessage)
+			}
+		})
+	}
+}
+
+func TestExplainErrors_Unrecognized(t *testing.T) {
+	// A comma is not missing between a keyword and a name
+	err := explainAt(t, "SELECT * FROM employees WHERE emp_id = 1 ORDER emp_id;\n", "emp_id;", "BY", ",")
+	if err.Code != "" || err.Message != "mismatched input" {
+		t.Errorf("Expected the parser message, got %s %q", err.Code, err.Message)
+	}
+	if err.ParserMessage != "" {
+		t.Errorf("Expected no parser message, got %q", err.ParserMessage)
+	}
+}
@@ -12,18 +12,151 @@ type PlSqlLexerBase struct {
 
 	lastToken   antlr.Token
 	reservedMap map[string]bool
+
+	recent     [5]int // Types of the last default channel tokens, most recent last
+	javaSource bool   // Inside the header of CREATE JAVA SOURCE
+	opaque     int    // WRAPPED or AS when an opaque payload follows, 0 otherwise
 }
 
 // NextToken from the character stream.
 func (l *PlSqlLexerBase) NextToken() antlr.Token {
+	if l.opaque != 0 && !isSpace(l.GetInputStream().LA(1)) {
+		if payload := l.opaqueBody(); payload != nil {
+			l.lastToken = payload
+			return payload
+		}
+	}
+
 	next := l.BaseLexer.NextToken() // Get next token
 	if next.GetChannel() == antlr.TokenDefaultChannel {
 		// Keep track of the last token on default channel
 		l.lastToken = next
+		l.trackHeader(next.GetTokenType())
 	}
 	return next
 }
 
+// SetInputStream resets the lexer to read from a new input, so that a lexer
+// instance can be reused for several scripts.
+func (l *PlSqlLexerBase) SetInputStream(input antlr.CharStream) {
+	l.lastToken = nil
+	l.recent = [5]int{}
+	l.javaSource = false
+	l.opaque = 0
+	l.BaseLexer.SetInputStream(input)
+}
+
+// trackHeader notes the headers of wrapped units and Java sources, whose
+// bodies are not PL/SQL and are returned as one OPAQUE_BODY token
+func (l *PlSqlLexerBase) trackHeader(tokenType int) {
+	copy(l.recent[:], l.recent[1:])
+	l.recent[len(l.recent)-1] = tokenType
+
+	switch tokenType {
+	case PlSqlLexerSEMICOLON, PlSqlLexerUSING:
+		l.javaSource = false
+	case PlSqlLexerSOURCE:
+		l.javaSource = l.recent[len(l.recent)-2] == PlSqlLexerJAVA
+	case PlSqlLexerAS:
+		if l.javaSource {
+			l.javaSource = false
+			l.opaque = PlSqlLexerAS
+		}
+	case PlSqlLexerWRAPPED:
+		if l.wrapsUnit() && l.endsLine() {
+			l.opaque = PlSqlLexerWRAPPED
+		}
+	}
+}
+
+// wrapsUnit reports whether the WRAPPED just read follows the name of a
+// package, procedure, function or type, optionally qualified by a schema
+func (l *PlSqlLexerBase) wrapsUnit() bool {
+	n := len(l.recent) - 1
+	kind := l.recent[n-2]
+	if l.recent[n-2] == PlSqlLexerPERIOD {
+		kind = l.recent[n-4]
+	}
+	switch kind {
+	case PlSqlLexerPACKAGE, PlSqlLexerBODY, PlSqlLexerPROCEDURE, PlSqlLexerFUNCTION, PlSqlLexerTYPE:
+		return true
+	}
+	return false
+}
+
+// endsLine reports whether only spaces follow on the current line
+func (l *PlSqlLexerBase) endsLine() bool {
+	input := l.GetInputStream()
+	for i := 1; ; i++ {
+		switch c := input.LA(i); {
+		case c == '\n' || c == antlr.TokenEOF:
+			return true
+		case !isSpace(c):
+			return false
+		}
+	}
+}
+
+// opaqueBody returns the payload of a wrapped unit or Java source as one
+// token, which runs up to the SQL*Plus slash line that ends the unit and
+// leaves out the whitespace before it. It returns nil when there is no
+// payload, or when a Java source is given as a string literal.
+func (l *PlSqlLexerBase) opaqueBody() antlr.Token {
+	defer func() { l.opaque = 0 }()
+
+	input := l.GetInputStream()
+	if l.opaque == PlSqlLexerAS && input.LA(1) == '\'' {
+		return nil
+	}
+
+	start, line, column := input.Index(), l.GetLine(), l.GetCharPositionInLine()
+	newline := l.lastToken == nil || l.lastToken.GetLine() < line
+	for {
+		// Whitespace belongs to the payload only when more payload follows
+		n := 1
+		for ; isSpace(input.LA(n)); n++ {
+			newline = newline || input.LA(n) == '\n'
+		}
+		if input.LA(n) == antlr.TokenEOF || newline && l.slashLineAt(n) {
+			break
+		}
+		for ; n > 0; n-- {
+			l.Interpreter.Consume(input)
+		}
+		newline = false
+	}
+
+	if input.Index() == start {
+		return nil
+	}
+	payload := l.GetTokenFactory().Create(l.GetTokenSourceCharStreamPair(), PlSqlLexerOPAQUE_BODY, "",
+		antlr.TokenDefaultChannel, start, input.Index()-1, line, column)
+	l.trackHeader(PlSqlLexerOPAQUE_BODY)
+	return payload
+}
+
+// slashLineAt reports whether the character at lookahead n is a slash that
+// only spaces follow on its line
+func (l *PlSqlLexerBase) slashLineAt(n int) bool {
+	input := l.GetInputStream()
+	if input.LA(n) != '/' {
+		return false
+	}
+	for i := n + 1; ; i++ {
+		switch c := input.LA(i); {
+		case c == '\n' || c == antlr.TokenEOF:
+			return true
+		case !isSpace(c):
+			return false
+		}
+	}
+}
+
+// isSpace reports whether c is a space, tab or line break
+func isSpace(c int) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n'
+}
+
 // IsRegexPossible returns true if the lexer can match a
 // regex literal.
 func (l *PlSqlLexerBase) IsNewlineAtPos(pos int) bool {
@@ -32,6 +32,11 @@
 package gen
 
 import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+
 	"github.com/antlr4-go/antlr/v4"
 )
 
@@ -40,6 +45,7 @@ type PlSqlParserBase struct {
 	*antlr.BaseParser
 	_isVersion12 bool
 	_isVersion10 bool
+	_isVersion23 bool
 }
 
 func (p *PlSqlParserBase) IsTableAlias() bool {
@@ -61,3 +67,29 @@ func (p *PlSqlParserBase) isVersion10() bool {
 func (p *PlSqlParserBase) SetVersion10(value bool) {
 	p._isVersion10 = value
 }
+
+func (p *PlSqlParserBase) isVersion23() bool {
+	return p._isVersion23
+}
+
+func (p *PlSqlParserBase) SetVersion23(value bool) {
+	p._isVersion23 = value
+}
+
+var grammarChecksum = sync.OnceValue(func() string {
+	PlSqlLexerInit()
+	PlSqlParserInit()
+
+	h := sha256.New()
+	for _, atn := range [][]int32{PlSqlLexerLexerStaticData.serializedATN, PlSqlParserParserStaticData.serializedATN} {
+		binary.Write(h, binary.LittleEndian, int64(len(atn)))
+		binary.Write(h, binary.LittleEndian, atn)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+})
+
+// GrammarChecksum returns a SHA-256 checksum of the serialized lexer and
+// parser ATNs, which changes whenever the grammar is regenerated
+func GrammarChecksum() string {
+	return grammarChecksum()
+}
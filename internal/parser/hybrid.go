@@ -0,0 +1,98 @@
+package parser
+
+import "sort"
+
+// hybridMaxErrors lets the parser recover from every error so that all failing
+// statements can be located
+const hybridMaxErrors = 1 << 20
+
+// parseHybrid parses the script with error recovery. Statements whose region
+// contains a syntax error are replaced by the statements the lexical splitter
+// finds there, typed UNKNOWN and marked as not parsed.
+func parseHybrid(input string, opts ParseOptions) ([]Statement, []SyntaxError, error) {
+	recovering := opts
+	recovering.MaxErrors = hybridMaxErrors
+
+	parsed, syntaxErrors, err := parseFull(input, recovering)
+	if err != nil || len(syntaxErrors) == 0 {
+		return parsed, syntaxErrors, err
+	}
+
+	lexical := splitLexical(input, opts.Terminators)
+	if len(lexical) == 0 {
+		return parsed, limitErrors(syntaxErrors, opts.MaxErrors), nil
+	}
+
+	// A lexical statement owns every position up to the start of the next one,
+	// so errors reported on a terminator belong to the statement it ends
+	failed := make([]bool, len(lexical))
+	for _, syntaxErr := range syntaxErrors {
+		failed[statementAt(lexical, syntaxErr.Line, syntaxErr.Column)] = true
+	}
+
+	result := make([]Statement, 0, len(parsed)+len(lexical))
+	for _, stmt := range parsed {
+		if !overlapsFailed(stmt, lexical, failed) {
+			result = append(result, stmt)
+		}
+	}
+	for i, stmt := range lexical {
+		if failed[i] {
+			stmt.Type = "UNKNOWN"
+			result = append(result, stmt)
+		}
+	}
+
+	sort.SliceStable(result, func(i, j int) bool {
+		return comparePosition(result[i].StartLine, result[i].StartColumn, result[j].StartLine, result[j].StartColumn) < 0
+	})
+
+	return result, limitErrors(syntaxErrors, opts.MaxErrors), nil
+}
+
+// overlapsFailed reports whether a parsed statement overlaps a failed lexical region
+func overlapsFailed(stmt Statement, lexical []Statement, failed []bool) bool {
+	for i, region := range lexical {
+		if !failed[i] {
+			continue
+		}
+		// The statement must end after the region starts and start before the next region
+		endsAfterStart := comparePosition(stmt.EndLine, stmt.EndColumn, region.StartLine, region.StartColumn) > 0
+		startsBeforeEnd := i+1 == len(lexical) ||
+			comparePosition(stmt.StartLine, stmt.StartColumn, lexical[i+1].StartLine, lexical[i+1].StartColumn) < 0
+		if endsAfterStart && startsBeforeEnd {
+			return true
+		}
+	}
+	return false
+}
+
+// statementAt returns the index of the statement that owns a position, which
+// is the last one starting at or before it, or the first one
+func statementAt(statements []Statement, line, column int) int {
+	i := sort.Search(len(statements), func(i int) bool {
+		return comparePosition(statements[i].StartLine, statements[i].StartColumn, line, column) > 0
+	})
+	if i > 0 {
+		i--
+	}
+	return i
+}
+
+// comparePosition orders two line and column positions
+func comparePosition(line1, column1, line2, column2 int) int {
+	switch {
+	case line1 != line2:
+		return line1 - line2
+	default:
+		return column1 - column2
+	}
+}
+
+// limitErrors truncates errors to the configured maximum
+func limitErrors(syntaxErrors []SyntaxError, maxErrors int) []SyntaxError {
+	if maxErrors > 0 && len(syntaxErrors) > maxErrors {
+		return syntaxErrors[:maxErrors]
+	}
+	return syntaxErrors
+}
@@ -0,0 +1,78 @@
+package parser
+
+import "testing"
+
+func TestParse_HybridMode(t *testing.T) {
+	input := `SELECT * FROM employees;
+FROBNICATE employees WITH extra force;
+DELETE FROM employees WHERE emp_id = 1;
+`
+
+	statements, syntaxErrors, err := Parse(input, ParseOptions{Mode: ModeHybrid, MaxErrors: 5})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) == 0 {
+		t.Errorf("Expected the unsupported statement to be reported as a syntax error")
+	}
+
+	expected := []struct {
+		content string
+		typ     string
+		parsed  bool
+	}{
+		{"SELECT * FROM employees", "SELECT", true},
+		{"FROBNICATE employees WITH extra force", "UNKNOWN", false},
+		{"DELETE FROM employees WHERE emp_id = 1", "DELETE", true},
+	}
+
+	if len(statements) != len(expected) {
+		for _, stmt := range statements {
+			t.Logf("%s %v %q", stmt.Type, stmt.Parsed, stmt.Content)
+		}
+		t.Fatalf("Expected %d statements, got %d", len(expected), len(statements))
+	}
+	for i, e := range expected {
+		stmt := statements[i]
+		if stmt.Content != e.content || stmt.Type != e.typ || stmt.Parsed != e.parsed {
+			t.Errorf("Statement %d: expected %s %v %q, got %s %v %q", i, e.typ, e.parsed, e.content, stmt.Type, stmt.Parsed, stmt.Content)
+		}
+	}
+}
+
+func TestParse_HybridModeWithoutErrors(t *testing.T) {
+	input := "SELECT * FROM employees; COMMIT;"
+
+	full, _, err := ParseString(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	hybrid, syntaxErrors, err := Parse(input, ParseOptions{Mode: ModeHybrid})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 0 {
+		t.Errorf("Expected no syntax errors, got %d", len(syntaxErrors))
+	}
+
+	if len(hybrid) != len(full) {
+		t.Fatalf("Expected %d statements, got %d", len(full), len(hybrid))
+	}
+	for i := range full {
+		if !hybrid[i].Parsed || hybrid[i].Content != full[i].Content {
+			t.Errorf("Statement %d: expected parsed %q, got %+v", i, full[i].Content, hybrid[i])
+		}
+	}
+}
+
+func TestComparePosition(t *testing.T) {
+	if comparePosition(1, 10, 2, 0) >= 0 {
+		t.Errorf("Earlier line should sort first")
+	}
+	if comparePosition(3, 4, 3, 2) <= 0 {
+		t.Errorf("Later column should sort last")
+	}
+	if comparePosition(5, 5, 5, 5) != 0 {
+		t.Errorf("Equal positions should compare equal")
+	}
+}
@@ -0,0 +1,199 @@
+package parser
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Codes of the warnings about identifiers that parse but that the target
+// release rejects or resolves to another object than it reads
+const (
+	CodeReservedIdentifier = "PLS-SPLIT-1005" // An unquoted name is a reserved word
+	CodeIdentifierTooLong  = "PLS-SPLIT-1006" // An identifier is longer than the target release allows
+	CodeQuotedCaseVariant  = "PLS-SPLIT-1007" // A quoted identifier differs from another only by case
+)
+
+// nameRules are the rules naming an object, column, variable or alias
+var nameRules = map[int]bool{
+	gen.PlSqlParserRULE_column_name:        true,
+	gen.PlSqlParserRULE_tableview_name:     true,
+	gen.PlSqlParserRULE_schema_object_name: true,
+	gen.PlSqlParserRULE_schema_name:        true,
+	gen.PlSqlParserRULE_identifier:         true,
+	gen.PlSqlParserRULE_variable_name:      true,
+	gen.PlSqlParserRULE_parameter_name:     true,
+	gen.PlSqlParserRULE_procedure_name:     true,
+	gen.PlSqlParserRULE_function_name:      true,
+	gen.PlSqlParserRULE_package_name:       true,
+	gen.PlSqlParserRULE_type_name:          true,
+	gen.PlSqlParserRULE_column_alias:       true,
+	gen.PlSqlParserRULE_table_alias:        true,
+	gen.PlSqlParserRULE_index_name:         true,
+	gen.PlSqlParserRULE_sequence_name:      true,
+	gen.PlSqlParserRULE_trigger_name:       true,
+	gen.PlSqlParserRULE_cursor_name:        true,
+	gen.PlSqlParserRULE_exception_name:     true,
+	gen.PlSqlParserRULE_record_name:        true,
+	gen.PlSqlParserRULE_synonym_name:       true,
+	gen.PlSqlParserRULE_constraint_name:    true,
+}
+
+// IdentifierChecker finds the identifiers of a script that the target release
+// rejects: unquoted names that are reserved words and identifiers longer than
+// the release allows. It also finds quoted identifiers that differ from
+// another identifier of the script only by case. It is passed to Parse in
+// ParseOptions.Listeners and its Warnings read after the parse.
+type IdentifierChecker struct {
+	*gen.BasePlSqlParserListener
+	index          *source.Index
+	version        Version
+	statementIndex int
+	names          []identifierName
+	warnings       []Warning
+}
+
+// identifierName is an occurrence of a name, as Oracle stores it
+type identifierName struct {
+	name           string // Upper case when unquoted, as written when quoted
+	quoted         bool
+	tok            antlr.Token
+	statementIndex int
+}
+
+// NewIdentifierChecker creates a checker for input targeting a release
+func NewIdentifierChecker(input string, version Version) *IdentifierChecker {
+	return &IdentifierChecker{
+		BasePlSqlParserListener: &gen.BasePlSqlParserListener{},
+		index:                   source.NewIndex(input),
+		version:                 version.resolve(),
+		statementIndex:          -1,
+	}
+}
+
+// SetStatementIndex implements StatementAware
+func (c *IdentifierChecker) SetStatementIndex(index int) {
+	c.statementIndex = index
+}
+
+// identifierLimit returns the maximum length of an identifier in bytes.
+// Oracle 12.2 raised it from 30 to 128, so 12c, which is 12.1 here, still
+// has the old limit.
+func (v Version) identifierLimit() int {
+	if v.AtLeast(Version18c) {
+		return 128
+	}
+	return 30
+}
+
+// EnterRegular_id checks an unquoted identifier
+func (c *IdentifierChecker) EnterRegular_id(ctx *gen.Regular_idContext) {
+	tok := ctx.GetStart()
+	text, word := tok.GetText(), strings.ToUpper(tok.GetText())
+	c.checkLength(tok, text, text)
+	c.names = append(c.names, identifierName{name: word, tok: tok, statementIndex: c.statementIndex})
+
+	if gen.ReservedWords[word] && namesObject(ctx) {
+		c.add(tok, CodeReservedIdentifier,
+			fmt.Sprintf("%s is a reserved word and is rejected as a name unless it is quoted, as in \"%s\"", word, word))
+	}
+}
+
+// EnterId_expression checks a quoted identifier
+func (c *IdentifierChecker) EnterId_expression(ctx *gen.Id_expressionContext) {
+	tok := childToken(ctx, gen.PlSqlLexerDELIMITED_ID)
+	if tok == nil {
+		return
+	}
+	text := tok.GetText()
+	name := strings.ReplaceAll(text[1:len(text)-1], `""`, `"`)
+	c.checkLength(tok, text, name)
+	c.names = append(c.names, identifierName{name: name, quoted: true, tok: tok, statementIndex: c.statementIndex})
+}
+
+// namesObject reports whether an identifier names an object, column,
+// variable or alias, rather than referring to a pseudocolumn or function such
+// as SYSDATE, USER or LEVEL in an expression or to a type
+func namesObject(ctx antlr.Tree) bool {
+	named := false
+	for parent := ctx.GetParent(); parent != nil; parent = parent.GetParent() {
+		switch rule := ruleIndex(parent); rule {
+		case gen.PlSqlParserRULE_expression, gen.PlSqlParserRULE_condition, gen.PlSqlParserRULE_type_spec:
+			return false
+		case gen.PlSqlParserRULE_query_block, gen.PlSqlParserRULE_unit_statement:
+			// The expressions of an enclosing query or statement do not
+			// hold the names of this one
+			return named
+		default:
+			named = named || nameRules[rule]
+		}
+	}
+	return named
+}
+
+// checkLength reports an identifier longer than the target release allows.
+// The length of a quoted identifier does not include its quotes.
+func (c *IdentifierChecker) checkLength(tok antlr.Token, text, name string) {
+	if limit := c.version.identifierLimit(); len(name) > limit {
+		c.add(tok, CodeIdentifierTooLong,
+			fmt.Sprintf("%s is %d bytes long, Oracle %s allows at most %d", text, len(name), c.version, limit))
+	}
+}
+
+// add records a warning about a token in the current statement
+func (c *IdentifierChecker) add(tok antlr.Token, code, message string) {
+	c.warnings = append(c.warnings, c.warning(tok, c.statementIndex, code, message))
+}
+
+// warning returns a warning about a token
+func (c *IdentifierChecker) warning(tok antlr.Token, statementIndex int, code, message string) Warning {
+	return Warning{
+		Code:           code,
+		Message:        message,
+		Line:           tok.GetLine(),
+		Column:         tok.GetColumn(),
+		StartOffset:    c.index.ByteOffset(tok.GetStart()),
+		EndOffset:      c.index.ByteOffset(tok.GetStop() + 1),
+		StatementIndex: statementIndex,
+	}
+}
+
+// Warnings returns the warnings of the walk in script order. Quoted
+// identifiers that differ from another only by case are found once every
+// identifier is known, and each spelling is reported where it first occurs.
+func (c *IdentifierChecker) Warnings() []Warning {
+	warnings := append([]Warning(nil), c.warnings...)
+
+	spellings := make(map[string][]string)
+	for _, n := range c.names {
+		folded := strings.ToUpper(n.name)
+		if !slices.Contains(spellings[folded], n.name) {
+			spellings[folded] = append(spellings[folded], n.name)
+		}
+	}
+
+	reported := make(map[string]bool)
+	for _, n := range c.names {
+		if !n.quoted || reported[n.name] {
+			continue
+		}
+		for _, other := range spellings[strings.ToUpper(n.name)] {
+			if other != n.name {
+				reported[n.name] = true
+				warnings = append(warnings, c.warning(n.tok, n.statementIndex, CodeQuotedCaseVariant,
+					fmt.Sprintf("\"%s\" differs from \"%s\" only by case, so they name different objects", n.name, other)))
+				break
+			}
+		}
+	}
+
+	sort.SliceStable(warnings, func(i, j int) bool {
+		return warnings[i].StartOffset < warnings[j].StartOffset
+	})
+	return warnings
+}
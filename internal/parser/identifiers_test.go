@@ -0,0 +1,118 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// checkIdentifiers parses input with an IdentifierChecker and returns its
+// warnings
+func checkIdentifiers(t *testing.T, input string, version Version) []Warning {
+	t.Helper()
+	checker := NewIdentifierChecker(input, version)
+	_, syntaxErrors, err := Parse(input, ParseOptions{
+		MaxErrors: 10,
+		Version:   version,
+		Listeners: []antlr.ParseTreeListener{checker},
+	})
+	if err != nil || len(syntaxErrors) != 0 {
+		t.Fatalf("Expected %q to parse, got %+v, %v", input, syntaxErrors, err)
+	}
+	return checker.Warnings()
+}
+
+func TestIdentifierChecker(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		version   Version
+		code      string
+		message   string
+		line      int
+		column    int
+		statement int
+	}{
+		{
+			name:      "reserved column name",
+			input:     "CREATE TABLE t (id NUMBER, level NUMBER);\n",
+			code:      CodeReservedIdentifier,
+			message:   `LEVEL is a reserved word and is rejected as a name unless it is quoted, as in "LEVEL"`,
+			line:      1,
+			column:    27,
+			statement: 0,
+		},
+		{
+			name:      "reserved variable name",
+			input:     "SELECT 1 FROM dual;\nDECLARE\n  comment VARCHAR2(10);\nBEGIN\n  NULL;\nEND;\n/\n",
+			code:      CodeReservedIdentifier,
+			message:   `COMMENT is a reserved word and is rejected as a name unless it is quoted, as in "COMMENT"`,
+			line:      3,
+			column:    2,
+			statement: 1,
+		},
+		{
+			name:      "long name before 12.2",
+			input:     "CREATE TABLE a_table_name_that_is_much_too_long (id NUMBER);\n",
+			version:   Version12c,
+			code:      CodeIdentifierTooLong,
+			message:   "a_table_name_that_is_much_too_long is 34 bytes long, Oracle 12c allows at most 30",
+			line:      1,
+			column:    13,
+			statement: 0,
+		},
+		{
+			name:      "quoted name differing by case",
+			input:     "CREATE TABLE \"Emp\" (id NUMBER);\nSELECT * FROM emp;\n",
+			code:      CodeQuotedCaseVariant,
+			message:   `"Emp" differs from "EMP" only by case, so they name different objects`,
+			line:      1,
+			column:    13,
+			statement: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := checkIdentifiers(t, tc.input, tc.version)
+			if len(warnings) != 1 {
+				t.Fatalf("Expected 1 warning, got %+v", warnings)
+			}
+
+			got := warnings[0]
+			if got.Code != tc.code || got.Message != tc.message {
+				t.Errorf("Expected %s %q, got %s %q", tc.code, tc.message, got.Code, got.Message)
+			}
+			if got.Line != tc.line || got.Column != tc.column || got.StatementIndex != tc.statement {
+				t.Errorf("Expected the warning at %d:%d in statement %d, got %d:%d in %d",
+					tc.line, tc.column, tc.statement, got.Line, got.Column, got.StatementIndex)
+			}
+			if got.EndOffset <= got.StartOffset {
+				t.Errorf("Expected the warning to cover the identifier, got %d-%d", got.StartOffset, got.EndOffset)
+			}
+		})
+	}
+}
+
+func TestIdentifierChecker_Valid(t *testing.T) {
+	tests := []struct {
+		input   string
+		version Version
+	}{
+		// Pseudocolumns and functions in expressions
+		{"SELECT level, SYSDATE, USER FROM dual CONNECT BY level <= 3;\n", VersionDefault},
+		{"BEGIN\n  IF USER = 'SYS' THEN\n    NULL;\n  END IF;\nEND;\n/\n", VersionDefault},
+		// Reserved words as quoted names
+		{"CREATE TABLE t (id NUMBER, \"LEVEL\" NUMBER);\n", VersionDefault},
+		// 128 bytes are allowed from 12.2 on
+		{"CREATE TABLE a_table_name_that_is_much_too_long (id NUMBER);\n", Version19c},
+		// A quoted upper case name is the unquoted name
+		{"CREATE TABLE \"EMP\" (id NUMBER);\nSELECT * FROM emp;\n", VersionDefault},
+	}
+
+	for _, tc := range tests {
+		if warnings := checkIdentifiers(t, tc.input, tc.version); len(warnings) != 0 {
+			t.Errorf("Expected no warnings for %q, got %+v", tc.input, warnings)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// ErrorKind tells which stage of parsing found a syntax error
+type ErrorKind string
+
+const (
+	ParserError   ErrorKind = "parser"   // The tokens do not follow the grammar
+	LexicalError  ErrorKind = "lexical"  // The characters do not form tokens
+	SemanticError ErrorKind = "semantic" // The tokens follow the grammar, but the compiler rejects them
+)
+
+// CodeUnterminatedIdentifier is the code of a quoted identifier that is not
+// closed
+const CodeUnterminatedIdentifier = "PLS-SPLIT-0010"
+
+// closingDelimiters maps the opening delimiters of q'[...]' quoting to their
+// closing ones. Other delimiters close themselves.
+var closingDelimiters = map[byte]byte{'[': ']', '(': ')', '{': '}', '<': '>'}
+
+// lexerError records the characters the lexer could not read. The lexer
+// skips them and goes on with the next token, so the parser never sees them.
+func (l *CustomErrorListener) lexerError(lexer *antlr.BaseLexer, line, column int) {
+	if len(l.Errors) >= l.MaxErrors {
+		return
+	}
+
+	input := lexer.GetInputStream()
+	start, stop := lexer.TokenStartCharIndex, input.Index()
+	token := &ErrorToken{Text: input.GetTextFromInterval(antlr.NewInterval(start, stop))}
+	if l.SourceText != "" {
+		if l.index == nil {
+			l.index = source.NewIndex(l.SourceText)
+		}
+		token.StartOffset = l.index.ByteOffset(start)
+		token.EndOffset = l.index.ByteOffset(stop + 1)
+	}
+
+	// A quoted identifier that is never closed runs to the end of the script
+	if strings.HasPrefix(token.Text, `"`) {
+		l.addLexical(line, column, CodeUnterminatedIdentifier, `unterminated quoted identifier, the closing " is missing`, token)
+		return
+	}
+	l.addLexical(line, column, CodeInvalidCharacter, fmt.Sprintf("invalid character %q", token.Text), token)
+}
+
+// reportUnclosed records the quotes and comments that are never closed. The
+// lexer reads them as a lone quote or as a slash and an asterisk, and goes on
+// to read what follows as code, which the parser then reports as errors of
+// its own. tokens are all the tokens of the script, on every channel.
+func reportUnclosed(errorListener *CustomErrorListener, tokens []antlr.Token, index *source.Index) {
+	var code []antlr.Token
+	for _, tok := range tokens {
+		if tok.GetChannel() == antlr.TokenDefaultChannel {
+			code = append(code, tok)
+		}
+	}
+
+	glued := func(i int) bool {
+		return i >= 0 && i+1 < len(code) && code[i].GetStop()+1 == code[i+1].GetStart()
+	}
+
+	for i, tok := range code {
+		switch tok.GetTokenType() {
+		case gen.PlSqlLexerSQ:
+			opener, message := tok, "unterminated string literal, the closing quote is missing"
+			end := index.ByteOffset(tok.GetStop() + 1)
+
+			// N'...', Q'[...]' and NQ'[...]' literals start with their prefix
+			if glued(i - 1) {
+				switch strings.ToUpper(code[i-1].GetText()) {
+				case "N":
+					opener = code[i-1]
+				case "Q", "NQ":
+					opener = code[i-1]
+					if end < len(index.Text()) {
+						delimiter := index.Text()[end]
+						if closing, ok := closingDelimiters[delimiter]; ok {
+							delimiter = closing
+						}
+						message = fmt.Sprintf("unterminated quoted string, the closing %c' is missing", delimiter)
+						end++
+					}
+				}
+			}
+
+			start := index.ByteOffset(opener.GetStart())
+			token := &ErrorToken{Text: index.Slice(start, end), StartOffset: start, EndOffset: end}
+			errorListener.addLexical(opener.GetLine(), opener.GetColumn(), CodeUnterminatedString, message, token)
+		case gen.PlSqlLexerSOLIDUS:
+			if glued(i) && code[i+1].GetTokenType() == gen.PlSqlLexerASTERISK {
+				start := index.ByteOffset(tok.GetStart())
+				token := &ErrorToken{Text: "/*", StartOffset: start, EndOffset: start + 2}
+				errorListener.addLexical(tok.GetLine(), tok.GetColumn(), CodeUnterminatedComment, "unterminated comment, the closing */ is missing", token)
+			}
+		}
+	}
+
+	errorListener.sortErrors()
+}
+
+// addLexical records a lexical error
+func (l *CustomErrorListener) addLexical(line, column int, code, message string, token *ErrorToken) {
+	context := ""
+	if l.SourceText != "" {
+		context = l.extractErrorContext(line, column)
+	}
+	l.Errors = append(l.Errors, SyntaxError{
+		Line:           line,
+		Column:         column,
+		Message:        message,
+		TokenText:      token.Text,
+		Token:          token,
+		Context:        context,
+		Kind:           LexicalError,
+		Code:           code,
+		StatementIndex: -1,
+	})
+}
+
+// sortErrors orders the errors by position, lexical errors first as they
+// cause the parser errors at the same position, and keeps at most MaxErrors
+func (l *CustomErrorListener) sortErrors() {
+	sort.SliceStable(l.Errors, func(i, j int) bool {
+		a, b := l.Errors[i], l.Errors[j]
+		if c := comparePosition(a.Line, a.Column, b.Line, b.Column); c != 0 {
+			return c < 0
+		}
+		return a.Kind == LexicalError && b.Kind != LexicalError
+	})
+	if len(l.Errors) > l.MaxErrors {
+		l.Errors = l.Errors[:l.MaxErrors]
+	}
+}
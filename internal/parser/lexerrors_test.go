@@ -0,0 +1,136 @@
+package parser
+
+import (
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// lexErrors lexes input and returns the lexical errors found
+func lexErrors(input string) []SyntaxError {
+	index := source.NewIndex(input)
+	listener := NewCustomErrorListener(10, input, 1)
+	listener.index = index
+
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	lexer.RemoveErrorListeners()
+	lexer.AddErrorListener(listener)
+	filter := newTerminatorFilter(newDirectiveFilter(lexer, nil, VersionDefault), index, Terminators{})
+
+	var tokens []antlr.Token
+	for {
+		tok := filter.NextToken()
+		tokens = append(tokens, tok)
+		if tok.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+	}
+	reportUnclosed(listener, tokens, index)
+	return listener.Errors
+}
+
+func TestLexicalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		code    string
+		message string
+		line    int
+		column  int
+		text    string
+		offset  int
+	}{
+		{
+			name:    "invalid character",
+			input:   "SELECT 'é' ` FROM dual;\n",
+			code:    CodeInvalidCharacter,
+			message: "invalid character \"`\"",
+			line:    1,
+			column:  11,
+			text:    "`",
+			offset:  12,
+		},
+		{
+			name:    "unterminated string",
+			input:   "SELECT * FROM dual;\nSELECT 'abc FROM dual;\n",
+			code:    CodeUnterminatedString,
+			message: "unterminated string literal, the closing quote is missing",
+			line:    2,
+			column:  7,
+			text:    "'",
+			offset:  27,
+		},
+		{
+			name:    "unterminated quoted string",
+			input:   "BEGIN\n  x := q'[abc;\nEND;\n",
+			code:    CodeUnterminatedString,
+			message: "unterminated quoted string, the closing ]' is missing",
+			line:    2,
+			column:  7,
+			text:    "q'[",
+			offset:  13,
+		},
+		{
+			name:    "unterminated comment",
+			input:   "SELECT 1 /* FROM dual;\n",
+			code:    CodeUnterminatedComment,
+			message: "unterminated comment, the closing */ is missing",
+			line:    1,
+			column:  9,
+			text:    "/*",
+			offset:  9,
+		},
+		{
+			name:    "unterminated identifier",
+			input:   "SELECT \"abc FROM dual;\n",
+			code:    CodeUnterminatedIdentifier,
+			message: "unterminated quoted identifier, the closing \" is missing",
+			line:    1,
+			column:  7,
+			text:    "\"abc FROM dual;\n",
+			offset:  7,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			errs := lexErrors(tc.input)
+			if len(errs) != 1 {
+				t.Fatalf("Expected 1 error, got %+v", errs)
+			}
+			err := errs[0]
+			if err.Kind != LexicalError || err.Code != tc.code || err.Message != tc.message {
+				t.Errorf("Expected lexical %s %q, got %s %s %q", tc.code, tc.message, err.Kind, err.Code, err.Message)
+			}
+			if err.Line != tc.line || err.Column != tc.column {
+				t.Errorf("Expected the error at %d:%d, got %d:%d", tc.line, tc.column, err.Line, err.Column)
+			}
+			if err.Token == nil || err.Token.Text != tc.text || err.Token.StartOffset != tc.offset {
+				t.Errorf("Expected %q at %d, got %+v", tc.text, tc.offset, err.Token)
+			}
+		})
+	}
+
+	if errs := lexErrors("SELECT q'[it's]' || N'x' FROM dual; /* done */\n"); len(errs) != 0 {
+		t.Errorf("Expected no errors, got %+v", errs)
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	listener := NewCustomErrorListener(2, "", 0)
+	listener.Errors = []SyntaxError{
+		{Line: 3, Column: 0, Kind: ParserError},
+		{Line: 1, Column: 4, Kind: ParserError},
+		{Line: 1, Column: 4, Kind: LexicalError},
+	}
+	listener.sortErrors()
+
+	if len(listener.Errors) != 2 {
+		t.Fatalf("Expected the errors to be cut to 2, got %d", len(listener.Errors))
+	}
+	if listener.Errors[0].Kind != LexicalError || listener.Errors[1].Line != 1 {
+		t.Errorf("Expected the lexical error first, got %+v", listener.Errors)
+	}
+}
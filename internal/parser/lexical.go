@@ -0,0 +1,249 @@
+package parser
+
+import (
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Mode selects how statement boundaries are found
+type Mode int
+
+const (
+	// ModeFull runs the ANTLR parser over the whole script
+	ModeFull Mode = iota
+	// ModeLexical finds boundaries from the token stream alone, without parsing
+	ModeLexical
+	// ModeHybrid runs the parser and falls back to lexical boundaries for
+	// statements that contain syntax errors
+	ModeHybrid
+)
+
+// SplitLexical splits a script using only the PlSqlLexer token stream. A small
+// state machine tracks BEGIN/END, CASE, DECLARE and CREATE ... AS/IS bodies and
+// SQL*Plus slash lines, so it finds the same boundaries as the parser for valid
+// scripts at a fraction of the cost. It never reports syntax errors and the
+// returned statements carry no parse tree.
+func SplitLexical(input string) []Statement {
+	return splitLexical(input, Terminators{})
+}
+
+// splitLexical splits a script lexically with the given initial SQL*Plus
+// terminator settings
+func splitLexical(input string, settings Terminators) []Statement {
+	index := source.NewIndex(input)
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	lexer.RemoveErrorListeners()
+
+	// Only the first branch of conditional compilation counts, as for the parser
+	directives := newDirectiveFilter(lexer, nil, VersionDefault)
+	terminators := newTerminatorFilter(directives, index, settings)
+
+	s := &lexicalSplitter{index: index}
+	for {
+		t := terminators.NextToken()
+		if t.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+		if t.GetChannel() == antlr.TokenDefaultChannel {
+			s.tokens = append(s.tokens, t)
+		}
+	}
+
+	return s.split()
+}
+
+// lexicalSplitter holds the default channel tokens of a script
+type lexicalSplitter struct {
+	index  *source.Index
+	tokens []antlr.Token
+}
+
+// blockLevel is an open BEGIN, CASE, DECLARE or subprogram body
+type blockLevel struct {
+	awaitsBegin bool // DECLARE and AS/IS levels are closed by the END of the BEGIN that follows them
+}
+
+func (s *lexicalSplitter) split() []Statement {
+	statements := make([]Statement, 0)
+
+	for i := 0; i < len(s.tokens); {
+		switch {
+		case s.is(i, gen.PlSqlLexerSEMICOLON), s.isSlashLine(i):
+			i++
+		case s.isSQLPlusCommand(i):
+			i = s.skipLine(i)
+		default:
+			last, next := s.statementEnd(i)
+			statements = append(statements, s.statement(i, last))
+			i = next
+		}
+	}
+
+	return statements
+}
+
+// statementEnd returns the index of the last token of the statement starting
+// at i and the index at which scanning resumes
+func (s *lexicalSplitter) statementEnd(i int) (int, int) {
+	if !s.isPLSQLUnit(i) {
+		// Plain SQL ends before its terminator, which is not part of the statement
+		for j := i; j < len(s.tokens); j++ {
+			if s.is(j, gen.PlSqlLexerSEMICOLON) || s.isSlashLine(j) {
+				return j - 1, j
+			}
+		}
+		return len(s.tokens) - 1, len(s.tokens)
+	}
+
+	var levels []blockLevel
+	inHeader := false // Between a subprogram name and its AS/IS
+	parens := 0
+
+	for j := i; j < len(s.tokens); j++ {
+		if s.isSlashLine(j) {
+			return j - 1, j
+		}
+
+		switch s.tokens[j].GetTokenType() {
+		case gen.PlSqlLexerLEFT_PAREN:
+			parens++
+		case gen.PlSqlLexerRIGHT_PAREN:
+			parens--
+		case gen.PlSqlLexerSEMICOLON:
+			// PL/SQL units include the semicolon that closes them
+			if len(levels) == 0 {
+				return j, j + 1
+			}
+			inHeader = false
+		case gen.PlSqlLexerPROCEDURE, gen.PlSqlLexerFUNCTION, gen.PlSqlLexerPACKAGE:
+			inHeader = true
+		case gen.PlSqlLexerTYPE:
+			inHeader = inHeader || s.is(j+1, gen.PlSqlLexerBODY)
+		case gen.PlSqlLexerIS, gen.PlSqlLexerAS:
+			if inHeader && parens == 0 {
+				inHeader = false
+				// Call specifications have no body
+				if !s.is(j+1, gen.PlSqlLexerLANGUAGE) && !s.is(j+1, gen.PlSqlLexerEXTERNAL) {
+					levels = append(levels, blockLevel{awaitsBegin: true})
+				}
+			}
+		case gen.PlSqlLexerDECLARE:
+			levels = append(levels, blockLevel{awaitsBegin: true})
+		case gen.PlSqlLexerBEGIN:
+			if n := len(levels); n > 0 && levels[n-1].awaitsBegin {
+				levels[n-1].awaitsBegin = false
+			} else {
+				levels = append(levels, blockLevel{})
+			}
+		case gen.PlSqlLexerCASE:
+			if !s.is(j-1, gen.PlSqlLexerEND) {
+				levels = append(levels, blockLevel{})
+			}
+		case gen.PlSqlLexerCOMPOUND:
+			if s.is(j+1, gen.PlSqlLexerTRIGGER) {
+				levels = append(levels, blockLevel{})
+			}
+		case gen.PlSqlLexerEND:
+			// END IF and END LOOP close statements that open no level
+			if s.is(j+1, gen.PlSqlLexerIF) || s.is(j+1, gen.PlSqlLexerLOOP) {
+				continue
+			}
+			if len(levels) > 0 {
+				levels = levels[:len(levels)-1]
+			}
+		}
+	}
+
+	return len(s.tokens) - 1, len(s.tokens)
+}
+
+// isPLSQLUnit reports whether the statement starting at i is an anonymous
+// block or a stored PL/SQL unit
+func (s *lexicalSplitter) isPLSQLUnit(i int) bool {
+	if s.is(i, gen.PlSqlLexerBEGIN) || s.is(i, gen.PlSqlLexerDECLARE) {
+		return true
+	}
+	if !s.is(i, gen.PlSqlLexerCREATE) {
+		return false
+	}
+
+	j := i + 1
+	if s.is(j, gen.PlSqlLexerOR) && s.is(j+1, gen.PlSqlLexerREPLACE) {
+		j += 2
+	}
+	if s.is(j, gen.PlSqlLexerEDITIONABLE) || s.is(j, gen.PlSqlLexerNONEDITIONABLE) || s.is(j, gen.PlSqlLexerEDITIONING) {
+		j++
+	}
+
+	switch {
+	case s.is(j, gen.PlSqlLexerPROCEDURE), s.is(j, gen.PlSqlLexerFUNCTION),
+		s.is(j, gen.PlSqlLexerPACKAGE), s.is(j, gen.PlSqlLexerTRIGGER), s.is(j, gen.PlSqlLexerTYPE):
+		return true
+	}
+	return false
+}
+
+// isSQLPlusCommand reports whether a SQL*Plus command starts at i
+func (s *lexicalSplitter) isSQLPlusCommand(i int) bool {
+	switch s.tokens[i].GetTokenType() {
+	case gen.PlSqlLexerPROMPT_MESSAGE, gen.PlSqlLexerSTART_CMD,
+		gen.PlSqlLexerSHOW, gen.PlSqlLexerEXIT, gen.PlSqlLexerWHENEVER, gen.PlSqlLexerTIMING:
+		return true
+	case gen.PlSqlLexerSET:
+		// SET TRANSACTION and SET CONSTRAINTS are SQL statements
+		return !s.is(i+1, gen.PlSqlLexerTRANSACTION) && !s.is(i+1, gen.PlSqlLexerCONSTRAINT) && !s.is(i+1, gen.PlSqlLexerCONSTRAINTS)
+	}
+	return false
+}
+
+// skipLine returns the index of the first token after the line of token i
+func (s *lexicalSplitter) skipLine(i int) int {
+	line := s.tokens[i].GetLine()
+	for i < len(s.tokens) && s.tokens[i].GetLine() == line {
+		i++
+	}
+	return i
+}
+
+// isSlashLine reports whether token i is a slash on a line of its own
+func (s *lexicalSplitter) isSlashLine(i int) bool {
+	if !s.is(i, gen.PlSqlLexerSOLIDUS) {
+		return false
+	}
+	t := s.tokens[i]
+	return s.index.AloneOnLine(s.index.ByteOffset(t.GetStart()), s.index.ByteOffset(t.GetStop()+1))
+}
+
+// is reports whether token i exists and has the given type
+func (s *lexicalSplitter) is(i int, tokenType int) bool {
+	return i >= 0 && i < len(s.tokens) && s.tokens[i].GetTokenType() == tokenType
+}
+
+// statement builds the statement spanning tokens first to last
+func (s *lexicalSplitter) statement(first, last int) Statement {
+	start := s.tokens[first]
+	stop := s.tokens[last]
+
+	startOffset := s.index.ByteOffset(start.GetStart())
+	endOffset := s.index.ByteOffset(stop.GetStop() + 1)
+	content := s.index.Slice(startOffset, endOffset)
+
+	// An opaque payload spans many lines
+	endLine, endColumn := stop.GetLine(), stop.GetColumn()+len(stop.GetText())
+	if stop.GetTokenType() == gen.PlSqlLexerOPAQUE_BODY {
+		endLine, endColumn = s.index.LineColumn(endOffset)
+	}
+
+	return Statement{
+		Content:     content,
+		StartLine:   start.GetLine(),
+		EndLine:     endLine,
+		StartColumn: start.GetColumn(),
+		EndColumn:   endColumn,
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
+		Type:        getDeterminedStatementType(content),
+		Wrapped:     isWrapped(content),
+	}
+}
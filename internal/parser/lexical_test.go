@@ -0,0 +1,172 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/test/samples"
+)
+
+// conformanceCorpus returns the valid scripts that both modes must split identically
+func conformanceCorpus() map[string]string {
+	corpus := make(map[string]string)
+	for name, sql := range samples.GetSimpleSQLSamples() {
+		corpus["simple/"+name] = sql
+	}
+	for name, sql := range samples.GetComplexSQLSamples() {
+		corpus["complex/"+name] = sql
+	}
+	for name, sql := range samples.GetOracle23aiSQLSamples() {
+		corpus["23ai/"+name] = sql
+	}
+
+	corpus["sqlplus_commands"] = `
+SET SERVEROUTPUT ON
+PROMPT Creating objects
+WHENEVER SQLERROR EXIT FAILURE
+SET TRANSACTION READ ONLY;
+CREATE TABLE audit_log (log_id NUMBER, msg VARCHAR2(200));
+SHOW ERRORS
+COMMIT;
+`
+	corpus["case_and_loops"] = `
+CREATE OR REPLACE FUNCTION grade(p_score NUMBER) RETURN VARCHAR2 IS
+  v_grade VARCHAR2(1);
+BEGIN
+  CASE
+    WHEN p_score >= 90 THEN v_grade := 'A';
+    ELSE v_grade := 'F';
+  END CASE;
+  FOR i IN 1 .. 3 LOOP
+    IF i = 2 THEN
+      v_grade := CASE v_grade WHEN 'A' THEN 'A' ELSE 'B' END;
+    END IF;
+  END LOOP;
+  RETURN v_grade;
+END grade;
+/
+SELECT grade(95) FROM dual;
+`
+	corpus["trigger_and_declare"] = `
+CREATE OR REPLACE TRIGGER emp_bi
+BEFORE INSERT ON employees
+FOR EACH ROW
+DECLARE
+  v_now DATE := SYSDATE;
+BEGIN
+  :new.created_at := v_now;
+END;
+/
+DECLARE
+  CURSOR c_emp IS SELECT emp_id FROM employees;
+BEGIN
+  FOR r IN c_emp LOOP
+    NULL;
+  END LOOP;
+END;
+/
+`
+	corpus["no_slash"] = `
+CREATE OR REPLACE PROCEDURE touch_all AS
+BEGIN
+  UPDATE employees SET updated_at = SYSDATE;
+END touch_all;
+DELETE FROM audit_log WHERE 1 = 0;
+`
+	corpus["wrapped_and_java"] = opaqueScript
+	corpus["sqlplus_terminators"] = terminatorScript
+
+	return corpus
+}
+
+// opaqueScript holds units whose bodies are not PL/SQL
+const opaqueScript = `CREATE OR REPLACE PACKAGE BODY hr.payroll wrapped
+a000000
+1
+abcd
+b
+w4vXa9s+TABLE/PACKAGE+9sPr/2q=
+
+/
+CREATE OR REPLACE AND COMPILE JAVA SOURCE NAMED "Hello" AS
+package com.acme;
+public class Hello {
+  // it's not PL/SQL; semicolons and quotes are fine
+  public static String hi() { return "it's"; }
+}
+/
+SELECT wrapped FROM dual;
+`
+
+func TestSplitLexical_OpaqueUnits(t *testing.T) {
+	statements := SplitLexical(opaqueScript)
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(statements))
+	}
+
+	body, java, query := statements[0], statements[1], statements[2]
+	if body.Type != "CREATE_PACKAGE_BODY" || !body.Wrapped {
+		t.Errorf("Expected a wrapped package body, got %s wrapped=%t", body.Type, body.Wrapped)
+	}
+	if !strings.HasSuffix(body.Content, "9sPr/2q=") || body.EndLine != 6 {
+		t.Errorf("Expected the payload to end before the blank line, got %q ending on line %d", body.Content, body.EndLine)
+	}
+	if java.Type != "CREATE_JAVA_SOURCE" || java.Wrapped {
+		t.Errorf("Expected a Java source, got %s wrapped=%t", java.Type, java.Wrapped)
+	}
+	if !strings.HasSuffix(java.Content, "return \"it's\"; }\n}") || java.EndLine != 14 || java.EndColumn != 1 {
+		t.Errorf("Expected the Java source up to its closing brace, got %q ending at %d:%d", java.Content, java.EndLine, java.EndColumn)
+	}
+	if query.Type != "SELECT" || query.Wrapped {
+		t.Errorf("Expected WRAPPED as a column name to be left alone, got %s wrapped=%t", query.Type, query.Wrapped)
+	}
+}
+
+func TestSplitLexical_Conformance(t *testing.T) {
+	for name, sql := range conformanceCorpus() {
+		t.Run(name, func(t *testing.T) {
+			expected, syntaxErrors, err := ParseString(sql)
+			if err != nil || len(syntaxErrors) > 0 {
+				t.Fatalf("Corpus script does not parse: %v %v", err, syntaxErrors)
+			}
+
+			actual := SplitLexical(sql)
+			if len(actual) != len(expected) {
+				t.Fatalf("Expected %d statements, got %d", len(expected), len(actual))
+			}
+
+			for i := range expected {
+				want, got := expected[i], actual[i]
+				// Lexical statements carry no tree and are not marked as parsed
+				want.Tree = nil
+				want.Parsed = false
+				if got != want {
+					t.Errorf("Statement %d differs\nparser:  %+v\nlexical: %+v", i, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestParse_LexicalMode(t *testing.T) {
+	statements, syntaxErrors, err := Parse("SELECT * FROM employees; BEGIN NULL; END;", ParseOptions{Mode: ModeLexical})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 0 {
+		t.Errorf("Lexical mode should not report syntax errors, got %d", len(syntaxErrors))
+	}
+
+	expected := []string{"SELECT * FROM employees", "BEGIN NULL; END;"}
+	if len(statements) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d", len(expected), len(statements))
+	}
+	for i, content := range expected {
+		if statements[i].Content != content {
+			t.Errorf("Statement %d: expected %q, got %q", i, content, statements[i].Content)
+		}
+		if statements[i].Tree != nil {
+			t.Errorf("Statement %d: lexical mode should not keep a parse tree", i)
+		}
+	}
+}
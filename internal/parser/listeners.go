@@ -0,0 +1,78 @@
+package parser
+
+import (
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// StatementAware is implemented by extra listeners that want to know which
+// statement the walk is currently inside
+type StatementAware interface {
+	// SetStatementIndex is called before every event with the index of the
+	// statement being built, or -1 outside of any statement
+	SetStatementIndex(index int)
+}
+
+// multiListener fans the events of a single walk out to the StatementListener
+// and any extra listeners. Typed gen.PlSqlParserListener callbacks are
+// dispatched to every listener that implements them.
+type multiListener struct {
+	primary *StatementListener
+	extras  []antlr.ParseTreeListener
+}
+
+// newMultiListener creates a listener that forwards to primary first, then extras
+func newMultiListener(primary *StatementListener, extras []antlr.ParseTreeListener) *multiListener {
+	return &multiListener{
+		primary: primary,
+		extras:  extras,
+	}
+}
+
+// notify passes the current statement index to a listener that asks for it
+func (m *multiListener) notify(listener antlr.ParseTreeListener) {
+	if aware, ok := listener.(StatementAware); ok {
+		aware.SetStatementIndex(m.primary.CurrentStatementIndex())
+	}
+}
+
+// VisitTerminal forwards a terminal node to all listeners
+func (m *multiListener) VisitTerminal(node antlr.TerminalNode) {
+	m.primary.VisitTerminal(node)
+	for _, extra := range m.extras {
+		m.notify(extra)
+		extra.VisitTerminal(node)
+	}
+}
+
+// VisitErrorNode forwards an error node to all listeners
+func (m *multiListener) VisitErrorNode(node antlr.ErrorNode) {
+	m.primary.VisitErrorNode(node)
+	for _, extra := range m.extras {
+		m.notify(extra)
+		extra.VisitErrorNode(node)
+	}
+}
+
+// EnterEveryRule forwards a rule entry to all listeners. The StatementListener
+// goes first so extras see the index of a statement starting at this rule.
+func (m *multiListener) EnterEveryRule(ctx antlr.ParserRuleContext) {
+	m.primary.EnterEveryRule(ctx)
+	ctx.EnterRule(m.primary)
+	for _, extra := range m.extras {
+		m.notify(extra)
+		extra.EnterEveryRule(ctx)
+		ctx.EnterRule(extra)
+	}
+}
+
+// ExitEveryRule forwards a rule exit to all listeners. Extras go first so they
+// still see the index of a statement ending at this rule.
+func (m *multiListener) ExitEveryRule(ctx antlr.ParserRuleContext) {
+	for _, extra := range m.extras {
+		m.notify(extra)
+		ctx.ExitRule(extra)
+		extra.ExitEveryRule(ctx)
+	}
+	ctx.ExitRule(m.primary)
+	m.primary.ExitEveryRule(ctx)
+}
@@ -0,0 +1,33 @@
+package parser
+
+import "regexp"
+
+// Wrapped units and Java sources carry a payload that is not PL/SQL, which the
+// lexer returns as one OPAQUE_BODY token. Their statements are recognized from
+// the header alone, as the payload may contain any word.
+var (
+	wrappedHeader = regexp.MustCompile(`(?is)^CREATE\s+(OR\s+REPLACE\s+)?((NON)?EDITIONABLE\s+)?` +
+		`(PACKAGE(\s+BODY)?|PROCEDURE|FUNCTION|TYPE(\s+BODY)?)\s+` +
+		`(("[^"]+"|[\w$#]+)\s*\.\s*)?("[^"]+"|[\w$#]+)\s+WRAPPED\b`)
+	javaSourceHeader = regexp.MustCompile(`(?is)^CREATE\s+(OR\s+REPLACE\s+)?(AND\s+(RESOLVE|COMPILE)\s+)?(NOFORCE\s+)?JAVA\s+SOURCE\b`)
+)
+
+// isWrapped reports whether a statement creates a unit obfuscated with the
+// wrap utility
+func isWrapped(text string) bool {
+	return wrappedHeader.MatchString(text)
+}
+
+// isJavaSource reports whether a statement creates a Java source
+func isJavaSource(text string) bool {
+	return javaSourceHeader.MatchString(text)
+}
+
+// opaqueHeader returns the header of a wrapped unit, up to WRAPPED, and the
+// text of any other statement unchanged
+func opaqueHeader(text string) string {
+	if loc := wrappedHeader.FindStringIndex(text); loc != nil {
+		return text[:loc[1]]
+	}
+	return text
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/antlr4-go/antlr/v4"
 	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
 )
 
 // SqlStatementContext is a placeholder until the ANTLR4 parser is generated
@@ -175,7 +176,12 @@ type Statement struct {
 	EndLine     int
 	StartColumn int
 	EndColumn   int
+	StartOffset int // Byte offset of the first character in the script
+	EndOffset   int // Byte offset just past the last character in the script
 	Type        string
+	Tree        antlr.ParserRuleContext // Parse tree of the statement
+	Parsed      bool                    // Whether the boundaries come from the parser rather than the token stream
+	Wrapped     bool                    // Whether the statement creates a unit obfuscated with the wrap utility
 }
 
 // SyntaxError represents a syntax error that occurred during parsing
@@ -183,8 +189,33 @@ type SyntaxError struct {
 	Line      int
 	Column    int
 	Message   string
-	TokenText string // Text of the offending token, if available
-	Context   string // Surrounding context for better error reporting
+	TokenText string      // Text of the offending token, if available
+	Token     *ErrorToken // Offending token, nil when the error is not on a token
+	Expected  []string    // Names of the tokens the parser expected, such as END, ; or REGULAR_ID
+	Context   string      // Surrounding context for better error reporting
+
+	// Kind tells whether the lexer or the parser found the error
+	Kind ErrorKind
+
+	// Code identifies errors whose cause was recognized, such as
+	// CodeMissingEnd. Their Message explains the cause, their position points
+	// at it, and ParserMessage keeps the parser's own message.
+	Code          string
+	ParserMessage string
+
+	StatementIndex int    // Index of the enclosing statement in the script, -1 when unknown
+	StatementType  string // Type of the enclosing statement
+	Statement      string // Text of the enclosing statement
+
+	parser bool // Reported by the parser, rather than by a directive or version check
+}
+
+// ErrorToken is the token at which a syntax error was reported
+type ErrorToken struct {
+	Text        string
+	Type        string // Symbolic name of the token type, such as REGULAR_ID or EOF, empty for lexical errors
+	StartOffset int    // Byte offset of the token in the script
+	EndOffset   int    // Byte offset just past the token
 }
 
 // CustomErrorListener captures syntax errors during parsing
@@ -194,6 +225,8 @@ type CustomErrorListener struct {
 	MaxErrors    int    // Maximum number of errors to capture
 	SourceText   string // The original source text for context
 	ContextLines int    // Number of context lines to include before and after the error
+
+	index *source.Index // Index of SourceText for token offsets, built when first needed
 }
 
 // NewCustomErrorListener creates a new error listener with the given max errors and source text
@@ -219,10 +252,18 @@ func (l *CustomErrorListener) SyntaxError(recognizer antlr.Recognizer, offending
 		return
 	}
 
+	// The lexer reports characters it cannot read
+	if lexer, ok := recognizer.(*antlr.BaseLexer); ok {
+		l.lexerError(lexer, line, column)
+		return
+	}
+
 	// Extract token text from the offending symbol if possible
 	var tokenText string
+	var token *ErrorToken
 	if symbol, ok := offendingSymbol.(antlr.Token); ok {
 		tokenText = symbol.GetText()
+		token = l.errorToken(symbol)
 	}
 
 	// Enhance error message for common nested block errors
@@ -240,14 +281,108 @@ func (l *CustomErrorListener) SyntaxError(recognizer antlr.Recognizer, offending
 
 	// Create and store the error
 	l.Errors = append(l.Errors, SyntaxError{
-		Line:      line,
-		Column:    column,
-		Message:   enhancedMsg,
-		TokenText: tokenText,
-		Context:   context,
+		Line:           line,
+		Column:         column,
+		Message:        enhancedMsg,
+		TokenText:      tokenText,
+		Token:          token,
+		Expected:       expectedTokens(recognizer),
+		Context:        context,
+		Kind:           ParserError,
+		StatementIndex: -1,
+		parser:         isParser(recognizer),
 	})
 }
 
+// errorToken describes an offending token, with byte offsets when the source
+// text is known
+func (l *CustomErrorListener) errorToken(symbol antlr.Token) *ErrorToken {
+	token := &ErrorToken{Text: symbol.GetText(), Type: tokenTypeName(symbol.GetTokenType())}
+	if l.SourceText == "" {
+		return token
+	}
+	if l.index == nil {
+		l.index = source.NewIndex(l.SourceText)
+	}
+	token.StartOffset = l.index.ByteOffset(symbol.GetStart())
+	token.EndOffset = l.index.ByteOffset(symbol.GetStop() + 1)
+	if token.EndOffset < token.StartOffset {
+		// EOF has no text
+		token.EndOffset = token.StartOffset
+	}
+	return token
+}
+
+// isParser reports whether an error comes from the parser
+func isParser(recognizer antlr.Recognizer) bool {
+	_, ok := recognizer.(antlr.Parser)
+	return ok
+}
+
+// tokenTypeName returns the symbolic name of a token type
+func tokenTypeName(tokenType int) string {
+	if tokenType == antlr.TokenEOF {
+		return "EOF"
+	}
+	names := gen.PlSqlLexerLexerStaticData.SymbolicNames
+	if tokenType <= 0 || tokenType >= len(names) {
+		return ""
+	}
+	return names[tokenType]
+}
+
+// expectedTokens returns the names of the tokens the parser could have
+// accepted where it failed. Tokens with a fixed text, such as keywords and
+// punctuation, are named by that text and others by their symbolic name.
+func expectedTokens(recognizer antlr.Recognizer) []string {
+	parser, ok := recognizer.(antlr.Parser)
+	if !ok {
+		return nil
+	}
+	expected := parser.GetExpectedTokens()
+	if expected == nil {
+		return nil
+	}
+
+	literals, symbols := parser.GetLiteralNames(), parser.GetSymbolicNames()
+	var names []string
+	for _, interval := range expected.GetIntervals() {
+		for tokenType := interval.Start; tokenType < interval.Stop; tokenType++ {
+			switch {
+			case tokenType == antlr.TokenEOF:
+				names = append(names, "EOF")
+			case tokenType < len(literals) && literals[tokenType] != "":
+				names = append(names, strings.Trim(literals[tokenType], "'"))
+			case tokenType < len(symbols) && symbols[tokenType] != "":
+				names = append(names, symbols[tokenType])
+			}
+		}
+	}
+	return names
+}
+
+// attributeErrors sets the statement of each error to the statement that
+// owns its position, and returns the statements. Statements are found
+// lexically, as the parse of a script with errors does not reliably delimit
+// them, and a statement owns every position up to the start of the next one.
+func attributeErrors(syntaxErrors []SyntaxError, input string, settings Terminators) []Statement {
+	if len(syntaxErrors) == 0 {
+		return nil
+	}
+	lexical := splitLexical(input, settings)
+	if len(lexical) == 0 {
+		return nil
+	}
+
+	for i := range syntaxErrors {
+		owner := statementAt(lexical, syntaxErrors[i].Line, syntaxErrors[i].Column)
+		syntaxErrors[i].StatementIndex = owner
+		syntaxErrors[i].StatementType = lexical[owner].Type
+		syntaxErrors[i].Statement = lexical[owner].Content
+	}
+	return lexical
+}
+
 // extractErrorContext extracts the source code context around an error location
 func (l *CustomErrorListener) extractErrorContext(line, column int) string {
 	lines := strings.Split(l.SourceText, "\n")
@@ -341,56 +476,139 @@ func ParseString(input string) ([]Statement, []SyntaxError, error) {
 	return ParseStringWithOptions(input, 1, 3)
 }
 
-// IsVersion12Enabled returns whether Oracle 12c features are enabled in the parser
-func IsVersion12Enabled() bool {
-	// Create a dummy parser to check its configuration
-	inputStream := antlr.NewInputStream("")
-	lexer := gen.NewPlSqlLexer(inputStream)
-	tokenStream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
-	parser := gen.NewPlSqlParser(tokenStream)
+// ParseOptions configures a call to Parse
+type ParseOptions struct {
+	MaxErrors    int     // Maximum number of syntax errors to collect
+	ContextLines int     // Number of context lines around each syntax error
+	Mode         Mode    // How statement boundaries are found; listeners only run in ModeFull
+	LLOnly       bool    // Skip the SLL prediction stage and always parse with full LL
+	Version      Version // Oracle release whose syntax is accepted; DefaultVersion when unset
 
-	// Set version 12 features (this is normally done in ParseStringWithOptions)
-	parser.SetVersion12(true)
+	// CCFlags holds the values of inquiry directives such as $$debug and of
+	// static constants such as pkg.flag for conditional compilation. When nil,
+	// the first branch of every $IF is parsed and conditions are not evaluated.
+	CCFlags map[string]any
 
-	// The method is unexported in the generated code, so we'll just return true
-	// since we just set it above
-	return true
+	// Terminators are the SQL*Plus settings that end statements at the start
+	// of the script. SET commands in the script change them as it goes.
+	Terminators Terminators
+
+	// BlockChecks reports END names and labels that do not match what they
+	// close, RAISE statements outside handlers and unreachable RETURNs as
+	// SemanticError errors
+	BlockChecks bool
+
+	// AllVersions accepts the syntax of every release instead of rejecting
+	// what Version does not support, so that a CompatibilityChecker can
+	// report it. Conditional compilation is still evaluated for Version.
+	AllVersions bool
+
+	// Listeners are invoked during the same walk as the StatementListener.
+	// Typed gen.PlSqlParserListener callbacks are dispatched to them as well,
+	// and listeners implementing StatementAware receive the statement index.
+	Listeners []antlr.ParseTreeListener
 }
 
 // ParseStringWithOptions parses a SQL string with configurable error handling options
 func ParseStringWithOptions(input string, maxErrors int, contextLines int) ([]Statement, []SyntaxError, error) {
-	// Setup the ANTLR lexer and parser
-	inputStream := antlr.NewInputStream(input)
-	lexer := gen.NewPlSqlLexer(inputStream)
+	return Parse(input, ParseOptions{
+		MaxErrors:    maxErrors,
+		ContextLines: contextLines,
+	})
+}
+
+// Parse parses a SQL string with the given options
+func Parse(input string, opts ParseOptions) ([]Statement, []SyntaxError, error) {
+	switch opts.Mode {
+	case ModeLexical:
+		return splitLexical(input, opts.Terminators), nil, nil
+	case ModeHybrid:
+		return parseHybrid(input, opts)
+	}
 
-	// Create token stream with error recovery
-	tokenStream := antlr.NewCommonTokenStream(lexer, antlr.TokenDefaultChannel)
+	return parseFull(input, opts)
+}
 
-	// Create the parser with error recovery
-	parser := gen.NewPlSqlParser(tokenStream)
+// parseFull parses the whole script with the ANTLR parser
+func parseFull(input string, opts ParseOptions) ([]Statement, []SyntaxError, error) {
+	maxErrors := opts.MaxErrors
+	contextLines := opts.ContextLines
 
-	// Enable version 12 features by default
-	parser.SetVersion12(true)
+	// Take a lexer and parser from the pool, reset to read the input in place
+	index := source.NewIndex(input)
+	set := acquireParser(index)
+	defer set.release()
+	tokenStream := set.tokenStream
+	parser := set.parser
 
-	// Set error recovery strategy
-	if maxErrors <= 1 {
-		// If we only want one error, use bail strategy for better performance
-		parser.SetErrorHandler(antlr.NewBailErrorStrategy())
+	// Pooled parsers keep the predicates of their previous parse
+	if opts.AllVersions {
+		opts.Version.configureAll(parser)
 	} else {
-		// For multiple errors, use default strategy with recovery
-		parser.SetErrorHandler(antlr.NewDefaultErrorStrategy())
+		opts.Version.configure(parser)
 	}
+	set.directives.reset(opts.CCFlags, opts.Version)
+	set.terminators.reset(index, opts.Terminators)
 
-	// Add custom error listener
+	// Errors are collected from the lexer as it reads tokens, which happens
+	// during the first stage
 	errorListener := NewCustomErrorListener(maxErrors, input, contextLines)
-	parser.RemoveErrorListeners()
-	parser.AddErrorListener(errorListener)
+	errorListener.index = index
+	set.lexer.AddErrorListener(errorListener)
+
+	// First stage: SLL prediction is much cheaper than full LL and succeeds on
+	// almost all valid input. Errors are not reported from this stage.
+	var tree antlr.ParseTree
+	if !opts.LLOnly {
+		parser.RemoveErrorListeners()
+		parser.SetErrorHandler(antlr.NewBailErrorStrategy())
+		parser.GetInterpreter().SetPredictionMode(antlr.PredictionModeSLL)
+
+		stageErrors := NewCustomErrorListener(1, "", 0)
+		parser.AddErrorListener(stageErrors)
+
+		tree = parser.Sql_script()
+		if len(stageErrors.Errors) > 0 || parser.HasError() {
+			tree = nil
+		}
+	}
+
+	// Second stage: re-parse with full LL prediction and error recovery, which
+	// either succeeds where SLL could not decide or reports the real errors
+	if tree == nil {
+		tokenStream.Seek(0)
+		parser.SetTokenStream(tokenStream)
+		parser.SetErrorHandler(antlr.NewDefaultErrorStrategy())
+		parser.GetInterpreter().SetPredictionMode(antlr.PredictionModeLL)
+		parser.RemoveErrorListeners()
+		parser.AddErrorListener(errorListener)
+
+		tree = parser.Sql_script()
+	}
+
+	// Errors in conditional compilation directives are found while lexing
+	set.directives.report(errorListener)
 
 	// Create the statement listener
 	listener := NewStatementListener(parser, tokenStream)
+	listener.source = index
 
-	// Start parsing
-	antlr.ParseTreeWalkerDefault.Walk(listener, parser.Sql_script())
+	// Syntax that the grammar does not guard with a predicate is checked
+	// against the target version during the walk
+	extras := opts.Listeners
+	if !opts.AllVersions && !opts.Version.AtLeast(DefaultVersion) {
+		extras = append(extras[:len(extras):len(extras)], &versionChecker{version: opts.Version, errors: errorListener})
+	}
+	if opts.BlockChecks {
+		extras = append(extras[:len(extras):len(extras)], newBlockChecker(errorListener))
+	}
+
+	// Walk the tree, running any extra listeners in the same pass
+	if len(extras) > 0 {
+		antlr.ParseTreeWalkerDefault.Walk(newMultiListener(listener, extras), tree)
+	} else {
+		antlr.ParseTreeWalkerDefault.Walk(listener, tree)
+	}
 
 	// Process the statements
 	statements := listener.Statements
@@ -407,11 +625,21 @@ func ParseStringWithOptions(input string, maxErrors int, contextLines int) ([]St
 			EndLine:     stmt.EndLine,
 			StartColumn: stmt.StartColumn,
 			EndColumn:   stmt.EndColumn,
+			StartOffset: stmt.StartOffset,
+			EndOffset:   stmt.EndOffset,
 			Type:        stmt.Type,
+			Tree:        stmt.Tree,
+			Parsed:      true,
+			Wrapped:     isWrapped(stmt.Content),
 		}
 	}
 
 	// Return the statements and any syntax errors
+	tokens := tokenStream.GetAllTokens()
+	reportUnclosed(errorListener, tokens, index)
+	if len(errorListener.Errors) > 0 {
+		explainErrors(errorListener, tokens, index, opts.Terminators)
+	}
 	return result, errorListener.Errors, nil
 }
 
@@ -422,7 +650,10 @@ type statementModel struct {
 	EndLine     int
 	StartColumn int
 	EndColumn   int
+	StartOffset int
+	EndOffset   int
 	Type        string
+	Tree        antlr.ParserRuleContext
 }
 
 // StatementListener listens for statements in the parse tree
@@ -433,6 +664,13 @@ type StatementListener struct {
 	tokenStream     *antlr.CommonTokenStream
 	currentType     string // Track the current statement type
 	plsqlBlockDepth int    // Track the nesting level of PL/SQL blocks
+
+	statementIndex int                     // Index of the last statement started
+	statementCtx   antlr.ParserRuleContext // Context of the open statement, nil between statements
+	lastStartLine  int
+	lastStartCol   int
+
+	source *source.Index // Script text; statement content is sliced from it when set
 }
 
 // NewStatementListener creates a new statement listener
@@ -444,6 +682,47 @@ func NewStatementListener(parser *gen.PlSqlParser, tokenStream *antlr.CommonToke
 		tokenStream:             tokenStream,
 		currentType:             "",
 		plsqlBlockDepth:         0,
+		statementIndex:          -1,
+	}
+}
+
+// textOf returns the script text from the first character of start to the
+// last character of stop, with its byte offsets
+func (l *StatementListener) textOf(start, stop antlr.Token) (string, int, int) {
+	if l.source == nil {
+		return l.tokenStream.GetTextFromTokens(start, stop), -1, -1
+	}
+	startOffset := l.source.ByteOffset(start.GetStart())
+	endOffset := l.source.ByteOffset(stop.GetStop() + 1)
+	return l.source.Slice(startOffset, endOffset), startOffset, endOffset
+}
+
+// CurrentStatementIndex returns the index of the statement being built, or -1
+// when the walk is not inside a statement. Statements that start at the same
+// position are merged by deduplication and therefore share an index.
+func (l *StatementListener) CurrentStatementIndex() int {
+	if l.statementCtx == nil {
+		return -1
+	}
+	return l.statementIndex
+}
+
+// trackStatement records that a statement starts at the given context
+func (l *StatementListener) trackStatement(ctx antlr.ParserRuleContext, startLine, startColumn int) {
+	if l.statementIndex < 0 || startLine != l.lastStartLine || startColumn != l.lastStartCol {
+		l.statementIndex++
+		l.lastStartLine = startLine
+		l.lastStartCol = startColumn
+	}
+	if l.statementCtx == nil {
+		l.statementCtx = ctx
+	}
+}
+
+// ExitEveryRule closes the open statement when its context is exited
+func (l *StatementListener) ExitEveryRule(ctx antlr.ParserRuleContext) {
+	if ctx == l.statementCtx {
+		l.statementCtx = nil
 	}
 }
 
@@ -463,7 +742,7 @@ func (l *StatementListener) EnterUnit_statement(ctx *gen.Unit_statementContext)
 	}
 
 	// Get the statement text
-	content := l.tokenStream.GetTextFromTokens(start, stop)
+	content, startOffset, endOffset := l.textOf(start, stop)
 
 	// Get position information
 	startLine := start.GetLine()
@@ -471,8 +750,14 @@ func (l *StatementListener) EnterUnit_statement(ctx *gen.Unit_statementContext)
 	endLine := stop.GetLine()
 	endColumn := stop.GetColumn() + len(stop.GetText())
 
+	// The opaque payload of a wrapped unit or Java source spans many lines
+	if stop.GetTokenType() == gen.PlSqlLexerOPAQUE_BODY && l.source != nil {
+		endLine, endColumn = l.source.LineColumn(endOffset)
+	}
+
 	// Determine the statement type
 	stmtType := getDeterminedStatementType(content)
+	l.trackStatement(ctx, startLine, startColumn)
 
 	// Add the statement to the list
 	l.Statements = append(l.Statements, statementModel{
@@ -481,7 +766,10 @@ func (l *StatementListener) EnterUnit_statement(ctx *gen.Unit_statementContext)
 		EndLine:     endLine,
 		StartColumn: startColumn,
 		EndColumn:   endColumn,
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
 		Type:        stmtType,
+		Tree:        ctx,
 	})
 }
 
@@ -501,7 +789,7 @@ func (l *StatementListener) EnterSql_statement(ctx *gen.Sql_statementContext) {
 	}
 
 	// Get the statement text
-	content := l.tokenStream.GetTextFromTokens(start, stop)
+	content, startOffset, endOffset := l.textOf(start, stop)
 
 	// Get position information
 	startLine := start.GetLine()
@@ -511,6 +799,7 @@ func (l *StatementListener) EnterSql_statement(ctx *gen.Sql_statementContext) {
 
 	// Determine the statement type
 	stmtType := getDeterminedStatementType(content)
+	l.trackStatement(ctx, startLine, startColumn)
 
 	// Add the statement to the list
 	l.Statements = append(l.Statements, statementModel{
@@ -519,7 +808,10 @@ func (l *StatementListener) EnterSql_statement(ctx *gen.Sql_statementContext) {
 		EndLine:     endLine,
 		StartColumn: startColumn,
 		EndColumn:   endColumn,
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
 		Type:        stmtType,
+		Tree:        ctx,
 	})
 }
 
@@ -621,13 +913,14 @@ func (l *StatementListener) EnterAnonymous_block(ctx *gen.Anonymous_blockContext
 	}
 
 	// Get the statement text
-	content := l.tokenStream.GetTextFromTokens(start, stop)
+	content, startOffset, endOffset := l.textOf(start, stop)
 
 	// Get position information
 	startLine := start.GetLine()
 	startColumn := start.GetColumn()
 	endLine := stop.GetLine()
 	endColumn := stop.GetColumn() + len(stop.GetText())
+	l.trackStatement(ctx, startLine, startColumn)
 
 	// Add the statement to the list
 	l.Statements = append(l.Statements, statementModel{
@@ -636,7 +929,10 @@ func (l *StatementListener) EnterAnonymous_block(ctx *gen.Anonymous_blockContext
 		EndLine:     endLine,
 		StartColumn: startColumn,
 		EndColumn:   endColumn,
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
 		Type:        "PLSQL_BLOCK",
+		Tree:        ctx,
 	})
 }
 
@@ -667,7 +963,7 @@ func (l *StatementListener) EnterTransaction_control_statements(ctx *gen.Transac
 	}
 
 	// Get the statement text
-	content := l.tokenStream.GetTextFromTokens(start, stop)
+	content, startOffset, endOffset := l.textOf(start, stop)
 
 	// Get position information
 	startLine := start.GetLine()
@@ -685,6 +981,7 @@ func (l *StatementListener) EnterTransaction_control_statements(ctx *gen.Transac
 	} else if strings.HasPrefix(upperContent, "SAVEPOINT") {
 		stmtType = "SAVEPOINT"
 	}
+	l.trackStatement(ctx, startLine, startColumn)
 
 	// Add the statement to the list
 	l.Statements = append(l.Statements, statementModel{
@@ -693,7 +990,10 @@ func (l *StatementListener) EnterTransaction_control_statements(ctx *gen.Transac
 		EndLine:     endLine,
 		StartColumn: startColumn,
 		EndColumn:   endColumn,
+		StartOffset: startOffset,
+		EndOffset:   endOffset,
 		Type:        stmtType,
+		Tree:        ctx,
 	})
 }
 
@@ -759,6 +1059,10 @@ func getDeterminedStatementType(text string) string {
 	} else if strings.HasPrefix(text, "MERGE") {
 		return "MERGE"
 	} else if strings.HasPrefix(text, "CREATE") {
+		if isJavaSource(text) {
+			return "CREATE_JAVA_SOURCE"
+		}
+		text = opaqueHeader(text)
 		if strings.Contains(text, "PACKAGE BODY") {
 			return "CREATE_PACKAGE_BODY"
 		} else if strings.Contains(text, "PACKAGE") {
@@ -5,6 +5,8 @@ import (
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/test/samples"
 )
 
 func TestDeduplicateStatements(t *testing.T) {
@@ -548,3 +550,197 @@ END process_data;
 		})
 	}
 }
+
+func TestParse_LLOnlyMatchesTwoStage(t *testing.T) {
+	input := `
+CREATE OR REPLACE PROCEDURE bump_salaries AS
+BEGIN
+  UPDATE employees SET salary = salary * 1.05;
+END bump_salaries;
+/
+SELECT * FROM employees;
+`
+
+	twoStage, _, err := Parse(input, ParseOptions{MaxErrors: 1})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	llOnly, _, err := Parse(input, ParseOptions{MaxErrors: 1, LLOnly: true})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(twoStage) != len(llOnly) {
+		t.Fatalf("Expected %d statements, got %d", len(llOnly), len(twoStage))
+	}
+	for i := range llOnly {
+		if twoStage[i].Content != llOnly[i].Content || twoStage[i].Type != llOnly[i].Type {
+			t.Errorf("Statement %d: expected %s %q, got %s %q", i, llOnly[i].Type, llOnly[i].Content, twoStage[i].Type, twoStage[i].Content)
+		}
+	}
+}
+
+func TestParse_TwoStageReportsErrors(t *testing.T) {
+	// The SLL stage fails silently, so errors must come from the LL stage
+	_, syntaxErrors, err := Parse("SELECT * FROM;\nDELETE FROM;", ParseOptions{MaxErrors: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 2 {
+		t.Errorf("Expected 2 syntax errors, got %d", len(syntaxErrors))
+	}
+	for _, syntaxErr := range syntaxErrors {
+		if syntaxErr.Context == "" {
+			t.Errorf("Expected context for error at line %d", syntaxErr.Line)
+		}
+	}
+}
+
+func TestParse_Version(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		version Version
+		valid   bool
+	}{
+		{"editionable on 12c", "CREATE OR REPLACE EDITIONABLE PROCEDURE p AS BEGIN NULL; END;", Version12c, true},
+		{"editionable on 11g", "CREATE OR REPLACE EDITIONABLE PROCEDURE p AS BEGIN NULL; END;", Version11g, false},
+		{"fetch first on 19c", "SELECT * FROM employees FETCH FIRST 10 ROWS ONLY;", Version19c, true},
+		{"fetch first on 11g", "SELECT * FROM employees FETCH FIRST 10 ROWS ONLY;", Version11g, false},
+		{"identity column on 11g", "CREATE TABLE t (id NUMBER GENERATED ALWAYS AS IDENTITY);", Version11g, false},
+		{"plsql in with on 12c", "WITH FUNCTION f RETURN NUMBER IS BEGIN RETURN 1; END;\nSELECT f FROM dual\n/", Version12c, true},
+		{"plsql in with on 11g", "WITH FUNCTION f RETURN NUMBER IS BEGIN RETURN 1; END;\nSELECT f FROM dual\n/", Version11g, false},
+		{"plain select on 10g", "SELECT * FROM employees;", Version10g, true},
+		{"default version", "SELECT * FROM employees OFFSET 5 ROWS;", VersionDefault, true},
+		{"if not exists on 23ai", "CREATE TABLE IF NOT EXISTS t (id NUMBER);", Version23ai, true},
+		{"if not exists on 21c", "CREATE TABLE IF NOT EXISTS t (id NUMBER);", Version21c, false},
+		{"if exists on 19c", "DROP TABLE IF EXISTS t;", Version19c, false},
+		{"select without from on 19c", "SELECT SYSDATE;", Version19c, false},
+		{"values rows on 21c", "INSERT INTO t VALUES (1), (2);", Version21c, false},
+		{"single values row on 11g", "INSERT INTO t VALUES (1);", Version11g, true},
+		{"domain on 19c", "CREATE DOMAIN d AS NUMBER;", Version19c, false},
+		{"annotations on 21c", "CREATE TABLE t (id NUMBER) ANNOTATIONS (Owner 'PMO');", Version21c, false},
+		{"domain as identifier on 19c", "SELECT domain, display FROM t;", Version19c, true},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, syntaxErrors, err := Parse(tc.input, ParseOptions{MaxErrors: 10, Version: tc.version})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if tc.valid && len(syntaxErrors) > 0 {
+				t.Errorf("Expected no errors, got %v", syntaxErrors)
+			}
+			if !tc.valid && len(syntaxErrors) == 0 {
+				t.Errorf("Expected a syntax error for %s", tc.version)
+			}
+		})
+	}
+}
+
+func TestParse_Oracle23aiSamples(t *testing.T) {
+	for name, sql := range samples.GetOracle23aiSQLSamples() {
+		t.Run(name, func(t *testing.T) {
+			_, syntaxErrors, err := Parse(sql, ParseOptions{MaxErrors: 10})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if len(syntaxErrors) > 0 {
+				t.Errorf("Expected no errors, got %v", syntaxErrors)
+			}
+		})
+	}
+}
+
+func TestParse_VersionErrorMessage(t *testing.T) {
+	_, syntaxErrors, err := Parse("SELECT *\nFROM employees FETCH FIRST 10 ROWS ONLY;", ParseOptions{MaxErrors: 10, Version: Version11g})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 1 {
+		t.Fatalf("Expected 1 syntax error, got %d", len(syntaxErrors))
+	}
+
+	got := syntaxErrors[0]
+	if got.Line != 2 || got.Column != 15 || got.Message != "FETCH requires Oracle 12c or later, but the target is 11g" {
+		t.Errorf("Unexpected error %d:%d %q", got.Line, got.Column, got.Message)
+	}
+}
+
+func TestParse_CCFlags(t *testing.T) {
+	script := "BEGIN\n  $IF $$legacy $THEN\n    this is not plsql;\n  $ELSE\n    NULL;\n  $END\nEND;"
+
+	// The first branch is parsed without flags
+	_, syntaxErrors, err := Parse(script, ParseOptions{MaxErrors: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) == 0 {
+		t.Error("Expected the first branch to be parsed without flags")
+	}
+
+	_, syntaxErrors, err = Parse(script, ParseOptions{MaxErrors: 10, CCFlags: map[string]any{"legacy": false}})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) > 0 {
+		t.Errorf("Expected only the live $ELSE branch to be parsed, got %v", syntaxErrors)
+	}
+
+	_, syntaxErrors, _ = Parse("BEGIN $ERROR 'unsupported' $END NULL; END;", ParseOptions{MaxErrors: 10, CCFlags: map[string]any{}})
+	if len(syntaxErrors) != 1 || syntaxErrors[0].Message != "$ERROR: unsupported" {
+		t.Errorf("Expected the $ERROR directive to be reported, got %v", syntaxErrors)
+	}
+}
+
+func TestParse_ErrorDetails(t *testing.T) {
+	_, syntaxErrors, err := Parse("SELECT * FROM dual;\nDELETE FROM;\n", ParseOptions{MaxErrors: 10})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) == 0 {
+		t.Fatal("Expected a syntax error")
+	}
+
+	got := syntaxErrors[0]
+	if got.Token == nil || got.Token.Text != ";" || got.Token.Type != "SEMICOLON" {
+		t.Fatalf("Expected the offending ; token, got %+v", got.Token)
+	}
+	if got.Token.StartOffset != 31 || got.Token.EndOffset != 32 {
+		t.Errorf("Expected the token at [31, 32), got [%d, %d)", got.Token.StartOffset, got.Token.EndOffset)
+	}
+	if len(got.Expected) == 0 {
+		t.Error("Expected the tokens the parser expected")
+	}
+	if got.StatementIndex != 1 || got.StatementType != "DELETE" || got.Statement != "DELETE FROM" {
+		t.Errorf("Expected the DELETE statement, got %d %s %q", got.StatementIndex, got.StatementType, got.Statement)
+	}
+}
+
+func TestAttributeErrors(t *testing.T) {
+	input := "-- setup\nSELECT * FROM dual;\nBEGIN\n  x := ;\nEND;\n/\n"
+	syntaxErrors := []SyntaxError{
+		{Line: 1, Column: 0, StatementIndex: -1},
+		{Line: 4, Column: 7, StatementIndex: -1},
+		{Line: 6, Column: 0, StatementIndex: -1},
+	}
+	attributeErrors(syntaxErrors, input, Terminators{})
+
+	expected := []int{0, 1, 1}
+	for i, want := range expected {
+		if syntaxErrors[i].StatementIndex != want {
+			t.Errorf("Error %d: expected statement %d, got %d", i, want, syntaxErrors[i].StatementIndex)
+		}
+	}
+	if syntaxErrors[1].StatementType != "PLSQL_BLOCK" || syntaxErrors[1].Statement != "BEGIN\n  x := ;\nEND;" {
+		t.Errorf("Expected the block, got %s %q", syntaxErrors[1].StatementType, syntaxErrors[1].Statement)
+	}
+
+	// Errors keep no statement when the script has none
+	none := []SyntaxError{{Line: 1, StatementIndex: -1}}
+	attributeErrors(none, "-- nothing\n", Terminators{})
+	if none[0].StatementIndex != -1 {
+		t.Errorf("Expected no statement, got %d", none[0].StatementIndex)
+	}
+}
@@ -0,0 +1,134 @@
+package parser
+
+import (
+	"sync"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// parserSet is a lexer, token stream and parser that are reused together.
+// The DFA and prediction context caches are not part of the set: the
+// generated recognizers share them process-wide, guarded by the ANTLR
+// runtime's own locks.
+type parserSet struct {
+	lexer       *gen.PlSqlLexer
+	directives  *directiveFilter
+	terminators *terminatorFilter
+	tokenStream *antlr.CommonTokenStream
+	parser      *gen.PlSqlParser
+}
+
+// poolParsers can be turned off to compare against fresh instances in benchmarks
+var poolParsers = true
+
+var parserPool = sync.Pool{
+	New: func() any {
+		return newParserSet()
+	},
+}
+
+func newParserSet() *parserSet {
+	lexer := gen.NewPlSqlLexer(emptyStream())
+	lexer.RemoveErrorListeners()
+	directives := newDirectiveFilter(lexer, nil, VersionDefault)
+	terminators := newTerminatorFilter(directives, source.NewIndex(""), Terminators{})
+	tokenStream := antlr.NewCommonTokenStream(terminators, antlr.TokenDefaultChannel)
+
+	return &parserSet{
+		lexer:       lexer,
+		directives:  directives,
+		terminators: terminators,
+		tokenStream: tokenStream,
+		parser:      gen.NewPlSqlParser(tokenStream),
+	}
+}
+
+// acquireParser returns a parser set, reset to read the indexed script without
+// copying it. Each set is used by a single goroutine until it is released.
+func acquireParser(index *source.Index) *parserSet {
+	set := getParserSet()
+	set.lexer.SetInputStream(source.NewStream(index))
+	set.directives.reset(nil, VersionDefault)
+	set.terminators.reset(index, Terminators{})
+	set.tokenStream.SetTokenSource(set.terminators)
+	set.parser.SetTokenStream(set.tokenStream)
+	return set
+}
+
+// getParserSet takes a set from the pool, or creates one when pooling is off
+func getParserSet() *parserSet {
+	if !poolParsers {
+		return newParserSet()
+	}
+	return parserPool.Get().(*parserSet)
+}
+
+// release returns the set to the pool. Parse trees and tokens produced by the
+// set remain valid, as they do not refer back to the reused instances.
+func (set *parserSet) release() {
+	if !poolParsers {
+		return
+	}
+
+	// Drop references to the last script so that it can be garbage collected
+	set.lexer.SetInputStream(emptyStream())
+	set.directives.reset(nil, VersionDefault)
+	set.terminators.reset(source.NewIndex(""), Terminators{})
+	set.tokenStream.SetTokenSource(set.terminators)
+	set.parser.SetTokenStream(set.tokenStream)
+	set.lexer.RemoveErrorListeners()
+	set.parser.RemoveErrorListeners()
+
+	parserPool.Put(set)
+}
+
+// warmupScript exercises the most common statement kinds
+const warmupScript = `
+SELECT e.emp_id, d.dept_name FROM employees e JOIN departments d ON e.dept_id = d.dept_id WHERE e.salary > 1000 ORDER BY 1;
+INSERT INTO audit_log (log_id, msg) VALUES (1, 'start');
+UPDATE employees SET salary = salary * 1.1 WHERE dept_id IN (SELECT dept_id FROM departments);
+DELETE FROM audit_log WHERE log_id < 0;
+MERGE INTO employees t USING staging s ON (t.emp_id = s.emp_id) WHEN MATCHED THEN UPDATE SET t.salary = s.salary;
+CREATE TABLE staging (emp_id NUMBER PRIMARY KEY, salary NUMBER(10, 2) DEFAULT 0);
+CREATE OR REPLACE VIEW rich_employees AS SELECT * FROM employees WHERE salary > 10000;
+CREATE OR REPLACE PACKAGE BODY payroll IS
+  FUNCTION total RETURN NUMBER IS
+    v_total NUMBER;
+  BEGIN
+    SELECT SUM(salary) INTO v_total FROM employees;
+    RETURN CASE WHEN v_total IS NULL THEN 0 ELSE v_total END;
+  END total;
+END payroll;
+/
+BEGIN
+  FOR r IN (SELECT emp_id FROM employees) LOOP
+    IF r.emp_id > 0 THEN
+      payroll.pay(r.emp_id);
+    END IF;
+  END LOOP;
+  COMMIT;
+EXCEPTION
+  WHEN OTHERS THEN
+    ROLLBACK;
+    RAISE;
+END;
+/
+`
+
+var warmOnce sync.Once
+
+// emptyStream returns a stream over an empty script
+func emptyStream() antlr.CharStream {
+	return source.NewStream(source.NewIndex(""))
+}
+
+// WarmUp parses a representative script once per process, so that the shared
+// DFA caches are populated before the first real parse. It is safe to call
+// from several goroutines; only the first call does any work.
+func WarmUp() {
+	warmOnce.Do(func() {
+		_, _, _ = Parse(warmupScript, ParseOptions{MaxErrors: 1})
+	})
+}
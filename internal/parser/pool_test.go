@@ -0,0 +1,84 @@
+package parser
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestParse_ReusedParserIsReset(t *testing.T) {
+	// Leave the pooled parser after a failed parse
+	if _, syntaxErrors, _ := ParseString("SELECT * FROM;"); len(syntaxErrors) == 0 {
+		t.Fatalf("Expected a syntax error")
+	}
+
+	// A SQL*Plus command on the first line is only recognised when the lexer
+	// has forgotten the previous script
+	statements, syntaxErrors, err := ParseString("PROMPT Loading\nSELECT * FROM employees;")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 0 {
+		t.Errorf("Expected no syntax errors, got %v", syntaxErrors)
+	}
+	if len(statements) != 1 || statements[0].Content != "SELECT * FROM employees" {
+		t.Errorf("Expected the SELECT statement, got %+v", statements)
+	}
+}
+
+func TestParse_Concurrent(t *testing.T) {
+	scripts := make([]string, 16)
+	expected := make([][]Statement, len(scripts))
+	for i := range scripts {
+		scripts[i] = fmt.Sprintf("SELECT * FROM tab%d;\nBEGIN\n  UPDATE tab%d SET col1 = %d;\nEND;\n/", i, i, i)
+
+		statements, _, err := ParseString(scripts[i])
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		expected[i] = statements
+	}
+
+	var wg sync.WaitGroup
+	for worker := 0; worker < 8; worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i, script := range scripts {
+				statements, _, err := ParseString(script)
+				if err != nil {
+					t.Errorf("Unexpected error: %v", err)
+					return
+				}
+				if len(statements) != len(expected[i]) {
+					t.Errorf("Script %d: expected %d statements, got %d", i, len(expected[i]), len(statements))
+					return
+				}
+				for j := range statements {
+					if statements[j].Content != expected[i][j].Content {
+						t.Errorf("Script %d statement %d: expected %q, got %q", i, j, expected[i][j].Content, statements[j].Content)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestWarmUp(t *testing.T) {
+	// WarmUp must be callable repeatedly and concurrently
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			WarmUp()
+		}()
+	}
+	wg.Wait()
+
+	_, syntaxErrors, err := ParseString(warmupScript)
+	if err != nil || len(syntaxErrors) != 0 {
+		t.Errorf("Warm-up script should parse cleanly: %v %v", err, syntaxErrors)
+	}
+}
@@ -0,0 +1,335 @@
+package parser
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// TerminatorOff turns a terminator off, like SET SQLTERMINATOR OFF
+const TerminatorOff rune = -1
+
+// Terminators are the SQL*Plus settings that end statements. The zero value
+// is the SQL*Plus default, except that blank lines never end statements.
+type Terminators struct {
+	SQL           rune // SET SQLTERMINATOR character, ';' when zero
+	Block         rune // SET BLOCKTERMINATOR character, '.' when zero
+	BlankLinesEnd bool // SET SQLBLANKLINES OFF: a blank line ends a SQL statement
+}
+
+// sqlTerminator returns the SQL terminator, ';' by default
+func (t Terminators) sqlTerminator() rune {
+	if t.SQL == 0 {
+		return ';'
+	}
+	return t.SQL
+}
+
+// blockTerminator returns the block terminator, '.' by default
+func (t Terminators) blockTerminator() rune {
+	if t.Block == 0 {
+		return '.'
+	}
+	return t.Block
+}
+
+// ParseTerminator parses the value of SET SQLTERMINATOR or SET
+// BLOCKTERMINATOR: ON for the default, OFF, or a single character that is
+// neither alphanumeric nor whitespace, optionally quoted
+func ParseTerminator(value string) (rune, bool) {
+	switch strings.ToUpper(value) {
+	case "ON":
+		return 0, true
+	case "OFF":
+		return TerminatorOff, true
+	}
+
+	if len(value) == 3 && (value[0] == '\'' || value[0] == '"') && value[2] == value[0] {
+		value = value[1:2]
+	}
+	c, size := utf8.DecodeRuneInString(value)
+	if size == 0 || size != len(value) || unicode.IsLetter(c) || unicode.IsDigit(c) || unicode.IsSpace(c) {
+		return 0, false
+	}
+	return c, true
+}
+
+// terminatorCommand finds SET commands that change the terminator settings
+var terminatorCommand = regexp.MustCompile(`(?im)^[ \t]*SET[ \t]+(.*[ \t])?(SQLT|BLO|SQLBL)`)
+
+// SetsTerminators reports whether a script holds a SET command that changes
+// the terminator settings, in which case its statements depend on all the
+// lines before them
+func SetsTerminators(text string) bool {
+	return terminatorCommand.MatchString(text)
+}
+
+// terminatorFilter is a token source that ends statements the way SQL*Plus
+// does, as it reads a script line by line. SET SQLTERMINATOR, SET
+// BLOCKTERMINATOR and SET SQLBLANKLINES commands in the script change the
+// settings from the next line on.
+//
+// A custom SQL terminator at the end of a line becomes a SEMICOLON, a line
+// holding only the block terminator becomes a slash line, and with
+// SQLBLANKLINES OFF the newline of a blank line after a SQL statement becomes
+// a SEMICOLON. PL/SQL units are only ended by slash and block terminator
+// lines, as in SQL*Plus, so their own terminators are left alone. A ; always
+// ends a SQL statement, as the database would reject it anyway.
+type terminatorFilter struct {
+	*directiveFilter
+	index    *source.Index
+	settings Terminators
+
+	pending     antlr.Token // Terminator split off the end of a token
+	head        []int       // Types of the first tokens of the open statement, nil between statements
+	commandLine int         // Line of the SQL*Plus command being read, 0 otherwise
+	lineStart   bool        // Only whitespace precedes the next token on its line
+}
+
+// headSize is the number of tokens that tell a PL/SQL unit from a SQL statement
+const headSize = 6
+
+// newTerminatorFilter wraps directives
+func newTerminatorFilter(directives *directiveFilter, index *source.Index, settings Terminators) *terminatorFilter {
+	f := &terminatorFilter{directiveFilter: directives}
+	f.reset(index, settings)
+	return f
+}
+
+// reset prepares the filter for a new script
+func (f *terminatorFilter) reset(index *source.Index, settings Terminators) {
+	f.index = index
+	f.settings = settings
+	f.pending = nil
+	f.head = nil
+	f.commandLine = 0
+	f.lineStart = true
+}
+
+// NextToken returns the next token with the SQL*Plus terminators resolved
+func (f *terminatorFilter) NextToken() antlr.Token {
+	if tok := f.pending; tok != nil {
+		f.pending = nil
+		return f.track(tok)
+	}
+
+	tok := f.directiveFilter.NextToken()
+	if tok.GetTokenType() == antlr.TokenEOF {
+		return tok
+	}
+	if tok.GetChannel() != antlr.TokenDefaultChannel {
+		return f.hidden(tok)
+	}
+
+	atLineStart := f.lineStart
+	f.lineStart = strings.HasSuffix(tok.GetText(), "\n")
+
+	if f.head == nil && atLineStart && f.isCommand(tok) {
+		f.commandLine = tok.GetLine()
+		if tok.GetTokenType() == gen.PlSqlLexerSET {
+			f.set(tok)
+		}
+		return tok
+	}
+	if tok.GetLine() == f.commandLine {
+		// Character values of SET commands are read as literals
+		if tok.GetTokenType() != gen.PlSqlLexerCHAR_STRING && f.isTerminatorValue(tok) {
+			return f.retype(tok, gen.PlSqlLexerCHAR_STRING, antlr.TokenDefaultChannel)
+		}
+		return tok
+	}
+
+	if block := f.settings.blockTerminator(); block != TerminatorOff && tok.GetText() == string(block) && f.aloneOnLine(tok) {
+		return f.track(f.retype(tok, gen.PlSqlLexerSOLIDUS, antlr.TokenDefaultChannel))
+	}
+
+	if sql := f.settings.sqlTerminator(); sql != ';' && sql != TerminatorOff && f.head != nil && !f.inBlock() && f.endsLine(tok) {
+		if text := tok.GetText(); strings.HasSuffix(text, string(sql)) {
+			return f.track(f.splitTerminator(tok, sql))
+		}
+	}
+
+	return f.track(tok)
+}
+
+// hidden handles a hidden channel token. With SQLBLANKLINES OFF, the newline
+// ending a blank line ends the open SQL statement.
+func (f *terminatorFilter) hidden(tok antlr.Token) antlr.Token {
+	text := tok.GetText()
+	if tok.GetTokenType() != gen.PlSqlLexerSPACES {
+		f.lineStart = strings.HasSuffix(text, "\n")
+		return tok
+	}
+	if text != "\n" {
+		return tok
+	}
+
+	blank := f.lineStart
+	f.lineStart = true
+	if blank && f.settings.BlankLinesEnd && f.head != nil && !f.inBlock() {
+		return f.track(f.retype(tok, gen.PlSqlLexerSEMICOLON, antlr.TokenDefaultChannel))
+	}
+	return tok
+}
+
+// track follows statement boundaries through the default channel tokens
+func (f *terminatorFilter) track(tok antlr.Token) antlr.Token {
+	switch {
+	case tok.GetTokenType() == gen.PlSqlLexerSOLIDUS && f.aloneOnLine(tok):
+		f.head = nil
+	case tok.GetTokenType() == gen.PlSqlLexerSEMICOLON && f.head != nil && !f.inBlock():
+		f.head = nil
+	case tok.GetTokenType() == gen.PlSqlLexerSEMICOLON && f.head == nil:
+		// A terminator on its own does not open a statement
+	case len(f.head) < headSize:
+		f.head = append(f.head, tok.GetTokenType())
+	}
+	return tok
+}
+
+// inBlock reports whether the open statement is a PL/SQL unit, which SQL*Plus
+// only ends at a slash or block terminator line
+func (f *terminatorFilter) inBlock() bool {
+	head := f.head
+	is := func(i int, tokenTypes ...int) bool {
+		if i >= len(head) {
+			return false
+		}
+		for _, tokenType := range tokenTypes {
+			if head[i] == tokenType {
+				return true
+			}
+		}
+		return false
+	}
+
+	if is(0, gen.PlSqlLexerBEGIN, gen.PlSqlLexerDECLARE) {
+		return true
+	}
+	if !is(0, gen.PlSqlLexerCREATE) {
+		return false
+	}
+
+	i := 1
+	if is(i, gen.PlSqlLexerOR) && is(i+1, gen.PlSqlLexerREPLACE) {
+		i += 2
+	}
+	if is(i, gen.PlSqlLexerAND) {
+		// AND COMPILE or AND RESOLVE before JAVA
+		i += 2
+	}
+	if is(i, gen.PlSqlLexerEDITIONABLE, gen.PlSqlLexerNONEDITIONABLE, gen.PlSqlLexerEDITIONING) {
+		i++
+	}
+	return is(i, gen.PlSqlLexerPROCEDURE, gen.PlSqlLexerFUNCTION, gen.PlSqlLexerPACKAGE,
+		gen.PlSqlLexerTRIGGER, gen.PlSqlLexerTYPE, gen.PlSqlLexerLIBRARY, gen.PlSqlLexerJAVA)
+}
+
+// isCommand reports whether tok starts a SQL*Plus command line
+func (f *terminatorFilter) isCommand(tok antlr.Token) bool {
+	switch tok.GetTokenType() {
+	case gen.PlSqlLexerPROMPT_MESSAGE, gen.PlSqlLexerSTART_CMD,
+		gen.PlSqlLexerSHOW, gen.PlSqlLexerEXIT, gen.PlSqlLexerWHENEVER, gen.PlSqlLexerTIMING:
+		return true
+	case gen.PlSqlLexerSET:
+		// SET TRANSACTION and SET CONSTRAINTS are SQL statements
+		words := strings.Fields(strings.ToUpper(f.restOfLine(tok)))
+		return len(words) == 0 || !strings.HasPrefix(words[0], "TRANSACTION") && !strings.HasPrefix(words[0], "CONSTRAINT")
+	}
+	return false
+}
+
+// set applies the terminator settings of a SET command. SQL*Plus accepts
+// abbreviations and several variables in one command; other variables and
+// invalid values are ignored.
+func (f *terminatorFilter) set(tok antlr.Token) {
+	words := strings.Fields(f.restOfLine(tok))
+	for i := 0; i+1 < len(words); i += 2 {
+		name, value := strings.ToUpper(words[i]), words[i+1]
+		switch {
+		case abbreviates(name, "SQLTERMINATOR", 4):
+			if c, ok := ParseTerminator(value); ok {
+				f.settings.SQL = c
+			}
+		case abbreviates(name, "BLOCKTERMINATOR", 3):
+			if c, ok := ParseTerminator(value); ok {
+				f.settings.Block = c
+			}
+		case abbreviates(name, "SQLBLANKLINES", 5):
+			switch strings.ToUpper(value) {
+			case "ON":
+				f.settings.BlankLinesEnd = false
+			case "OFF":
+				f.settings.BlankLinesEnd = true
+			}
+		}
+	}
+}
+
+// isTerminatorValue reports whether tok is a single character that is not
+// part of an identifier, such as the # of SET SQLTERMINATOR #
+func (f *terminatorFilter) isTerminatorValue(tok antlr.Token) bool {
+	c, size := utf8.DecodeRuneInString(tok.GetText())
+	return size == len(tok.GetText()) && !unicode.IsLetter(c) && !unicode.IsDigit(c)
+}
+
+// splitTerminator returns tok as a SEMICOLON when it is the terminator, or
+// the part of tok before the terminator, leaving the terminator pending
+func (f *terminatorFilter) splitTerminator(tok antlr.Token, terminator rune) antlr.Token {
+	text := tok.GetText()
+	if text == string(terminator) {
+		return f.retype(tok, gen.PlSqlLexerSEMICOLON, antlr.TokenDefaultChannel)
+	}
+
+	// Identifier characters such as # are read into the word before them
+	stop := tok.GetStop()
+	f.pending = f.GetTokenFactory().Create(tok.GetSource(), gen.PlSqlLexerSEMICOLON, string(terminator),
+		antlr.TokenDefaultChannel, stop, stop, tok.GetLine(), tok.GetColumn()+utf8.RuneCountInString(text)-1)
+
+	word := strings.TrimSuffix(text, string(terminator))
+	return f.GetTokenFactory().Create(tok.GetSource(), relex(word, tok.GetTokenType()), word,
+		antlr.TokenDefaultChannel, tok.GetStart(), stop-1, tok.GetLine(), tok.GetColumn())
+}
+
+// relex returns the type of the single token that text lexes to, or
+// fallback when it is not a single token
+func relex(text string, fallback int) int {
+	lexer := gen.NewPlSqlLexer(antlr.NewInputStream(text))
+	lexer.RemoveErrorListeners()
+	tok := lexer.NextToken()
+	if tok.GetStart() != 0 || tok.GetStop() != utf8.RuneCountInString(text)-1 {
+		return fallback
+	}
+	return tok.GetTokenType()
+}
+
+// restOfLine returns the text after tok up to the end of its line
+func (f *terminatorFilter) restOfLine(tok antlr.Token) string {
+	text := f.index.Text()
+	end := f.index.ByteOffset(tok.GetStop() + 1)
+	if newline := strings.IndexByte(text[end:], '\n'); newline >= 0 {
+		return text[end : end+newline]
+	}
+	return text[end:]
+}
+
+// endsLine reports whether only whitespace follows tok on its line
+func (f *terminatorFilter) endsLine(tok antlr.Token) bool {
+	return strings.TrimSpace(f.restOfLine(tok)) == ""
+}
+
+// aloneOnLine reports whether only whitespace surrounds tok on its line
+func (f *terminatorFilter) aloneOnLine(tok antlr.Token) bool {
+	return f.index.AloneOnLine(f.index.ByteOffset(tok.GetStart()), f.index.ByteOffset(tok.GetStop()+1))
+}
+
+// abbreviates reports whether name is full or an abbreviation of it that is
+// at least minimum characters long
+func abbreviates(name, full string, minimum int) bool {
+	return len(name) >= minimum && strings.HasPrefix(full, name)
+}
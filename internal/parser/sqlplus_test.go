@@ -0,0 +1,142 @@
+package parser
+
+import (
+	"testing"
+)
+
+// terminatorScript changes the SQL*Plus terminators as it goes
+const terminatorScript = `SET SQLTERMINATOR #
+SELECT emp_id FROM employees ORDER BY 1 DESC#
+SELECT * FROM dual WHERE note = 'a#b;'
+#
+SET BLOCKTERMINATOR !
+BEGIN
+  NULL;
+END;
+!
+SET SQLBLANKLINES OFF
+SET SQLT ON
+DELETE FROM audit_log
+
+COMMIT;
+`
+
+func TestSplitLexical_Terminators(t *testing.T) {
+	statements := SplitLexical(terminatorScript)
+
+	expected := []struct {
+		content string
+		line    int
+	}{
+		{"SELECT emp_id FROM employees ORDER BY 1 DESC", 2},
+		{"SELECT * FROM dual WHERE note = 'a#b;'", 3},
+		{"BEGIN\n  NULL;\nEND;", 6},
+		{"DELETE FROM audit_log", 12},
+		{"COMMIT", 14},
+	}
+	if len(statements) != len(expected) {
+		t.Fatalf("Expected %d statements, got %d: %+v", len(expected), len(statements), statements)
+	}
+	for i, want := range expected {
+		if statements[i].Content != want.content || statements[i].StartLine != want.line {
+			t.Errorf("Statement %d: expected %q on line %d, got %q on line %d", i, want.content, want.line, statements[i].Content, statements[i].StartLine)
+		}
+	}
+	if statements[0].EndColumn != 44 {
+		t.Errorf("Expected the first statement to end before the terminator, got column %d", statements[0].EndColumn)
+	}
+}
+
+func TestSplitLexical_TerminatorSettings(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Terminators
+		script   string
+		expected []string
+	}{
+		{
+			name:     "defaults",
+			script:   "SELECT 1 FROM dual\n\nFROM t;\nBEGIN NULL; END;\n.\n",
+			expected: []string{"SELECT 1 FROM dual\n\nFROM t", "BEGIN NULL; END;"},
+		},
+		{
+			name:     "custom SQL terminator",
+			settings: Terminators{SQL: '!'},
+			script:   "SELECT 1 FROM dual !\nSELECT 2 FROM dual;\n",
+			expected: []string{"SELECT 1 FROM dual", "SELECT 2 FROM dual"},
+		},
+		{
+			name:     "terminator inside a line",
+			settings: Terminators{SQL: '!'},
+			script:   "SELECT 1 FROM dual WHERE 1 != 2\n!\n",
+			expected: []string{"SELECT 1 FROM dual WHERE 1 != 2"},
+		},
+		{
+			name:     "PL/SQL units ignore the SQL terminator",
+			settings: Terminators{SQL: '#'},
+			script:   "BEGIN\n  x := y #\n  NULL;\nEND;\n/\n",
+			expected: []string{"BEGIN\n  x := y #\n  NULL;\nEND;"},
+		},
+		{
+			name:     "block terminator off",
+			settings: Terminators{Block: TerminatorOff},
+			script:   "SELECT 1 FROM dual\n.\n",
+			expected: []string{"SELECT 1 FROM dual\n."},
+		},
+		{
+			name:     "blank lines end SQL",
+			settings: Terminators{BlankLinesEnd: true},
+			script:   "SELECT 1\n\nFROM dual;\nBEGIN\n\n  NULL;\nEND;\n/\n",
+			expected: []string{"SELECT 1", "FROM dual", "BEGIN\n\n  NULL;\nEND;"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statements := splitLexical(tt.script, tt.settings)
+			if len(statements) != len(tt.expected) {
+				t.Fatalf("Expected %d statements, got %d: %+v", len(tt.expected), len(statements), statements)
+			}
+			for i, want := range tt.expected {
+				if statements[i].Content != want {
+					t.Errorf("Statement %d: expected %q, got %q", i, want, statements[i].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestParseTerminator(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected rune
+		ok       bool
+	}{
+		{"#", '#', true},
+		{"'!'", '!', true},
+		{"on", 0, true},
+		{"OFF", TerminatorOff, true},
+		{"x", 0, false},
+		{"##", 0, false},
+		{"", 0, false},
+	}
+
+	for _, tt := range tests {
+		c, ok := ParseTerminator(tt.value)
+		if c != tt.expected || ok != tt.ok {
+			t.Errorf("ParseTerminator(%q) = %q, %t; expected %q, %t", tt.value, c, ok, tt.expected, tt.ok)
+		}
+	}
+}
+
+func TestSetsTerminators(t *testing.T) {
+	if !SetsTerminators("SELECT 1 FROM dual;\n  set sqlt #\n") {
+		t.Error("Expected SET SQLT to change the terminators")
+	}
+	if !SetsTerminators("SET ECHO ON BLOCKTERMINATOR !\n") {
+		t.Error("Expected a later BLOCKTERMINATOR variable to change the terminators")
+	}
+	if SetsTerminators("SET SERVEROUTPUT ON\nUPDATE t SET sqlt = 1;\n") {
+		t.Error("Expected other SET commands and UPDATE ... SET to be ignored")
+	}
+}
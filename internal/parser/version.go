@@ -0,0 +1,171 @@
+package parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+)
+
+// Version is an Oracle Database release whose syntax the parser accepts
+type Version int
+
+const (
+	// VersionDefault selects DefaultVersion
+	VersionDefault Version = iota
+	Version10g
+	Version11g
+	Version12c
+	Version18c
+	Version19c
+	Version21c
+	Version23ai
+)
+
+// DefaultVersion is the release targeted when no version is configured
+const DefaultVersion = Version23ai
+
+// String returns the release name, such as 19c
+func (v Version) String() string {
+	switch v.resolve() {
+	case Version10g:
+		return "10g"
+	case Version11g:
+		return "11g"
+	case Version12c:
+		return "12c"
+	case Version18c:
+		return "18c"
+	case Version19c:
+		return "19c"
+	case Version21c:
+		return "21c"
+	case Version23ai:
+		return "23ai"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// resolve returns DefaultVersion for VersionDefault and v otherwise
+func (v Version) resolve() Version {
+	if v == VersionDefault {
+		return DefaultVersion
+	}
+	return v
+}
+
+// AtLeast reports whether v is the given release or a later one
+func (v Version) AtLeast(other Version) bool {
+	return v.resolve() >= other.resolve()
+}
+
+// release returns the DBMS_DB_VERSION.VERSION and RELEASE of the first
+// release of v
+func (v Version) release() (int, int) {
+	switch v.resolve() {
+	case Version10g:
+		return 10, 1
+	case Version11g:
+		return 11, 1
+	case Version12c:
+		return 12, 1
+	case Version18c:
+		return 18, 0
+	case Version19c:
+		return 19, 0
+	case Version21c:
+		return 21, 0
+	default:
+		return 23, 0
+	}
+}
+
+// dbmsDBVersion returns the value of a DBMS_DB_VERSION constant, such as
+// dbms_db_version.ver_le_12_1, on release v. Other names are NULL.
+func (v Version) dbmsDBVersion(name string) any {
+	constant, ok := strings.CutPrefix(name, "dbms_db_version.")
+	if !ok {
+		return nil
+	}
+
+	version, release := v.release()
+	switch constant {
+	case "version":
+		return version
+	case "release":
+		return release
+	}
+
+	limit, ok := strings.CutPrefix(constant, "ver_le_")
+	if !ok {
+		return nil
+	}
+	maxVersion, maxRelease, hasRelease := strings.Cut(limit, "_")
+	vmax, err := strconv.Atoi(maxVersion)
+	if err != nil {
+		return nil
+	}
+	if !hasRelease {
+		return version <= vmax
+	}
+	rmax, err := strconv.Atoi(maxRelease)
+	if err != nil {
+		return nil
+	}
+	return version < vmax || version == vmax && release <= rmax
+}
+
+// configure sets the grammar's version predicates. The 10g predicate guards
+// syntax that was removed after 10g, the 12c and 23ai predicates syntax added
+// in those releases.
+func (v Version) configure(parser *gen.PlSqlParser) {
+	parser.SetVersion10(v.resolve() == Version10g)
+	parser.SetVersion12(v.AtLeast(Version12c))
+	parser.SetVersion23(v.AtLeast(Version23ai))
+}
+
+// configureAll sets the grammar's version predicates to accept the syntax of
+// every release, for reporting what v does not support. Syntax removed after
+// 10g is only accepted when v is 10g.
+func (v Version) configureAll(parser *gen.PlSqlParser) {
+	parser.SetVersion10(v.resolve() == Version10g)
+	parser.SetVersion12(true)
+	parser.SetVersion23(true)
+}
+
+// versionedRules maps the grammar rules of syntax that the grammar accepts
+// without a version predicate to the release that introduced the syntax
+var versionedRules = map[int]Version{
+	gen.PlSqlParserRULE_offset_clause:   Version12c,
+	gen.PlSqlParserRULE_fetch_clause:    Version12c,
+	gen.PlSqlParserRULE_identity_clause: Version12c,
+}
+
+// versionChecker reports syntax introduced after the target version as syntax
+// errors. It runs during the statement walk that follows the parse.
+type versionChecker struct {
+	antlr.BaseParseTreeListener
+	version Version
+	errors  *CustomErrorListener
+}
+
+// EnterEveryRule reports a rule that requires a later release
+func (c *versionChecker) EnterEveryRule(ctx antlr.ParserRuleContext) {
+	required, ok := versionedRules[ctx.GetRuleIndex()]
+	if !ok || c.version.AtLeast(required) {
+		return
+	}
+
+	start := ctx.GetStart()
+	msg := fmt.Sprintf("%s requires Oracle %s or later, but the target is %s", strings.ToUpper(start.GetText()), required, c.version)
+	c.errors.SyntaxError(nil, start, start.GetLine(), start.GetColumn(), msg, nil)
+}
+
+// IsVersion12Enabled returns whether Oracle 12c features are enabled when no
+// version is configured
+func IsVersion12Enabled() bool {
+	return DefaultVersion.AtLeast(Version12c)
+}
@@ -0,0 +1,275 @@
+package parser
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Codes of the warnings about scripts that parse but are likely to run
+// differently in SQL*Plus than they read
+const (
+	CodeUnitWithoutSlash     = "PLS-SPLIT-1001" // A PL/SQL unit is not followed by a / line
+	CodeSemicolonAfterSlash  = "PLS-SPLIT-1002" // A ; follows a / line
+	CodeBlankLineInStatement = "PLS-SPLIT-1003" // A SQL statement holds a blank line while SQLBLANKLINES is off
+	CodeSubstitutionVariable = "PLS-SPLIT-1004" // A string holds a substitution variable while DEFINE is on
+)
+
+// Warning is something in a script that parses but is likely to misbehave
+// when the script is run in SQL*Plus
+type Warning struct {
+	Code           string
+	Message        string
+	Line           int     // 1-based line of the cause
+	Column         int     // 0-based column of the cause
+	StartOffset    int     // Byte offset of the cause
+	EndOffset      int     // Byte offset just past the cause
+	StatementIndex int     // Index of the enclosing statement, -1 when there is none
+	Required       Version // Release introducing the cause, for CodeUnsupportedSyntax
+}
+
+// Warnings returns the warnings about a script, in script order. Statements
+// are found lexically, so the script does not need to parse, and SQL*Plus
+// settings are followed through the SET commands of the script.
+func Warnings(input string, settings Terminators) []Warning {
+	index := source.NewIndex(input)
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	lexer.RemoveErrorListeners()
+	terminators := newTerminatorFilter(newDirectiveFilter(lexer, nil, VersionDefault), index, settings)
+
+	s := &lexicalSplitter{index: index}
+	for {
+		t := terminators.NextToken()
+		if t.GetTokenType() == antlr.TokenEOF {
+			break
+		}
+		if t.GetChannel() == antlr.TokenDefaultChannel {
+			s.tokens = append(s.tokens, t)
+		}
+	}
+
+	w := &warner{lexicalSplitter: s, statements: s.split()}
+	w.readSettings()
+	w.checkUnits()
+	w.checkSlashes()
+	w.checkBlankLines()
+	w.checkSubstitutions()
+
+	sort.SliceStable(w.warnings, func(i, j int) bool {
+		return w.warnings[i].StartOffset < w.warnings[j].StartOffset
+	})
+	return w.warnings
+}
+
+// warner finds the warnings of a lexically split script
+type warner struct {
+	*lexicalSplitter
+	statements []Statement
+	settings   []sessionSettings // Settings from each SET command on
+	warnings   []Warning
+}
+
+// sessionSettings are the SQL*Plus settings that warnings depend on, from a
+// byte offset of the script on
+type sessionSettings struct {
+	offset     int
+	blankLines bool // SET SQLBLANKLINES ON
+	define     rune // Substitution variable prefix, 0 with SET DEFINE OFF
+}
+
+// readSettings follows the SET SQLBLANKLINES, SET DEFINE and SET SCAN
+// commands of the script. SQL*Plus starts with blank lines ending SQL
+// statements and with & as the substitution prefix.
+func (w *warner) readSettings() {
+	current := sessionSettings{define: '&'}
+	w.settings = []sessionSettings{current}
+
+	for i, tok := range w.tokens {
+		if tok.GetTokenType() != gen.PlSqlLexerSET || !w.isSQLPlusCommand(i) {
+			continue
+		}
+
+		// The SET of an UPDATE can start a line too
+		end := w.index.ByteOffset(tok.GetStop() + 1)
+		if w.statementAt(end) >= 0 {
+			continue
+		}
+		line := w.index.Text()[end:]
+		if newline := strings.IndexByte(line, '\n'); newline >= 0 {
+			line = line[:newline]
+		}
+
+		words := strings.Fields(line)
+		for j := 0; j+1 < len(words); j += 2 {
+			name, value := strings.ToUpper(words[j]), strings.ToUpper(words[j+1])
+			switch {
+			case abbreviates(name, "SQLBLANKLINES", 5):
+				current.blankLines = value == "ON"
+			case abbreviates(name, "DEFINE", 3):
+				if c, ok := ParseTerminator(words[j+1]); ok {
+					switch c {
+					case 0:
+						current.define = '&'
+					case TerminatorOff:
+						current.define = 0
+					default:
+						current.define = c
+					}
+				}
+			case abbreviates(name, "SCAN", 4):
+				// SET SCAN is the obsolete form of SET DEFINE ON and OFF
+				if value == "OFF" {
+					current.define = 0
+				} else if value == "ON" && current.define == 0 {
+					current.define = '&'
+				}
+			}
+		}
+
+		current.offset = end
+		w.settings = append(w.settings, current)
+	}
+}
+
+// settingsAt returns the settings in effect at a byte offset
+func (w *warner) settingsAt(offset int) sessionSettings {
+	i := sort.Search(len(w.settings), func(i int) bool { return w.settings[i].offset > offset })
+	return w.settings[i-1]
+}
+
+// checkUnits warns about PL/SQL units that are not followed by a / line.
+// SQL*Plus keeps reading such a unit into its buffer, so it runs later
+// together with what follows, or not at all.
+func (w *warner) checkUnits() {
+	for k, stmt := range w.statements {
+		if !isPLSQLType(stmt.Type) {
+			continue
+		}
+		next := sort.Search(len(w.tokens), func(i int) bool {
+			return w.index.ByteOffset(w.tokens[i].GetStart()) >= stmt.EndOffset
+		})
+		if w.isSlashLine(next) {
+			continue
+		}
+		w.add(CodeUnitWithoutSlash, stmt.EndOffset, stmt.EndOffset, k,
+			fmt.Sprintf("the PL/SQL unit starting on line %d is not followed by a / line, so SQL*Plus does not run it", stmt.StartLine))
+	}
+}
+
+// checkSlashes warns about a ; after a / line, which SQL*Plus does not read
+// as an empty statement
+func (w *warner) checkSlashes() {
+	for i := 1; i < len(w.tokens); i++ {
+		if w.is(i, gen.PlSqlLexerSEMICOLON) && w.isSlashLine(i-1) {
+			start := w.index.ByteOffset(w.tokens[i].GetStart())
+			w.add(CodeSemicolonAfterSlash, start, start+1, -1, "; after a / line, SQL*Plus reads it as a new statement")
+		}
+	}
+}
+
+// checkBlankLines warns about blank lines inside SQL statements, which end
+// the statement in SQL*Plus unless SQLBLANKLINES is on. PL/SQL units may hold
+// blank lines.
+func (w *warner) checkBlankLines() {
+	for k, stmt := range w.statements {
+		if isPLSQLType(stmt.Type) || w.settingsAt(stmt.StartOffset).blankLines {
+			continue
+		}
+
+		offset := stmt.StartOffset
+		for _, line := range strings.SplitAfter(stmt.Content, "\n") {
+			if strings.TrimSpace(line) == "" && offset > stmt.StartOffset && offset < stmt.EndOffset {
+				w.add(CodeBlankLineInStatement, offset, offset+len(line), k,
+					"blank line inside a SQL statement, SQL*Plus ends the statement here unless SQLBLANKLINES is ON")
+				break
+			}
+			offset += len(line)
+		}
+	}
+}
+
+// checkSubstitutions warns about string literals holding what SQL*Plus reads
+// as a substitution variable, such as the &T of 'AT&T', while DEFINE is on
+func (w *warner) checkSubstitutions() {
+	for _, tok := range w.tokens {
+		switch tok.GetTokenType() {
+		case gen.PlSqlLexerCHAR_STRING, gen.PlSqlLexerNATIONAL_CHAR_STRING_LIT:
+		default:
+			continue
+		}
+
+		start := w.index.ByteOffset(tok.GetStart())
+		k := w.statementAt(start)
+		if k < 0 {
+			continue // A SQL*Plus command
+		}
+
+		define := w.settingsAt(start).define
+		if define == 0 {
+			continue
+		}
+		if at, name := substitutionVariable(tok.GetText(), define); name != "" {
+			w.add(CodeSubstitutionVariable, start+at, start+at+len(name), k,
+				fmt.Sprintf("%s in a string is replaced by SQL*Plus unless DEFINE is OFF", name))
+		}
+	}
+}
+
+// substitutionVariable returns the byte offset and text of the first
+// substitution variable in text, such as &name or &&1, or an empty name
+func substitutionVariable(text string, define rune) (int, string) {
+	prefix := string(define)
+	for i := strings.Index(text, prefix); i >= 0; {
+		end := i + len(prefix)
+		if strings.HasPrefix(text[end:], prefix) {
+			end += len(prefix)
+		}
+		name := end
+		for name < len(text) && isVariableByte(text[name]) {
+			name++
+		}
+		if name > end {
+			return i, text[i:name]
+		}
+
+		next := strings.Index(text[end:], prefix)
+		if next < 0 {
+			break
+		}
+		i = end + next
+	}
+	return 0, ""
+}
+
+// isVariableByte reports whether c can be part of a substitution variable name
+func isVariableByte(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// statementAt returns the index of the statement that holds a byte offset,
+// or -1 when it is outside every statement
+func (w *warner) statementAt(offset int) int {
+	k := sort.Search(len(w.statements), func(k int) bool { return w.statements[k].EndOffset > offset })
+	if k == len(w.statements) || w.statements[k].StartOffset > offset {
+		return -1
+	}
+	return k
+}
+
+// add records a warning
+func (w *warner) add(code string, start, end, statementIndex int, message string) {
+	line, column := w.index.LineColumn(start)
+	w.warnings = append(w.warnings, Warning{
+		Code:           code,
+		Message:        message,
+		Line:           line,
+		Column:         column,
+		StartOffset:    start,
+		EndOffset:      end,
+		StatementIndex: statementIndex,
+	})
+}
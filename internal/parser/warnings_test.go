@@ -0,0 +1,96 @@
+package parser
+
+import (
+	"testing"
+)
+
+func TestWarnings(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		code      string
+		line      int
+		column    int
+		statement int
+	}{
+		{
+			name:      "unit without slash",
+			input:     "SELECT * FROM dual;\nCREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\n",
+			code:      CodeUnitWithoutSlash,
+			line:      5,
+			column:    4,
+			statement: 1,
+		},
+		{
+			name:      "semicolon after slash",
+			input:     "BEGIN\n  NULL;\nEND;\n/\n;\n",
+			code:      CodeSemicolonAfterSlash,
+			line:      5,
+			statement: -1,
+		},
+		{
+			name:      "blank line in statement",
+			input:     "SELECT emp_id\n\nFROM employees;\n",
+			code:      CodeBlankLineInStatement,
+			line:      2,
+			statement: 0,
+		},
+		{
+			name:      "substitution variable",
+			input:     "INSERT INTO vendors (name) VALUES ('AT&T');\n",
+			code:      CodeSubstitutionVariable,
+			line:      1,
+			column:    38,
+			statement: 0,
+		},
+		{
+			name:      "custom define prefix",
+			input:     "SET DEFINE ^\nSELECT 'AT&T', '^^user' FROM dual;\n",
+			code:      CodeSubstitutionVariable,
+			line:      2,
+			column:    16,
+			statement: 0,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			warnings := Warnings(tc.input, Terminators{})
+			if len(warnings) != 1 {
+				t.Fatalf("Expected 1 warning, got %+v", warnings)
+			}
+			got := warnings[0]
+			if got.Code != tc.code || got.Line != tc.line || got.Column != tc.column || got.StatementIndex != tc.statement {
+				t.Errorf("Expected %s at %d:%d in statement %d, got %+v", tc.code, tc.line, tc.column, tc.statement, got)
+			}
+			if got.Message == "" {
+				t.Error("Expected a message")
+			}
+		})
+	}
+}
+
+func TestWarnings_Settings(t *testing.T) {
+	clean := []string{
+		"CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\n/\nSELECT * FROM dual;\n",
+		"SET DEFINE OFF\nINSERT INTO vendors (name) VALUES ('AT&T');\n",
+		"SET SCAN OFF\nSELECT 'R&D' FROM dual;\n",
+		"SELECT 'a & b' FROM dual;\n",
+		"SET SQLBLANKLINES ON\nSELECT emp_id\n\nFROM employees;\n",
+		"BEGIN\n  NULL;\n\n  NULL;\nEND;\n/\n",
+		"UPDATE t\nSET define = 'x&y'\nWHERE 1 = 0;\n",
+	}
+	for _, input := range clean {
+		// Only the UPDATE warns, as its SET is not a command
+		warnings := Warnings(input, Terminators{})
+		if input[0] == 'U' {
+			if len(warnings) != 1 || warnings[0].Code != CodeSubstitutionVariable {
+				t.Errorf("Expected a substitution warning for %q, got %+v", input, warnings)
+			}
+			continue
+		}
+		if len(warnings) != 0 {
+			t.Errorf("Expected no warnings for %q, got %+v", input, warnings)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// Package source maps the rune offsets used by the ANTLR runtime back to byte
+// offsets, lines and columns in the original script text.
+package source
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// Index answers position queries for a single script
+type Index struct {
+	text        string
+	runeCount   int
+	checkpoints []int // byte offset of every checkpointInterval-th rune; nil for ASCII input
+	lineStarts  []int // byte offset of the first character of each line
+}
+
+// checkpointInterval is the number of runes between two checkpoints. The
+// table costs a small fraction of the script size, where a full rune to byte
+// table would cost eight bytes per rune.
+const checkpointInterval = 64
+
+// NewIndex builds an index for the given script text
+func NewIndex(text string) *Index {
+	idx := &Index{
+		text:       text,
+		lineStarts: make([]int, 1, strings.Count(text, "\n")+1),
+	}
+
+	ascii := true
+	for i := 0; i < len(text); i++ {
+		if text[i] >= utf8.RuneSelf {
+			ascii = false
+		}
+		if text[i] == '\n' {
+			idx.lineStarts = append(idx.lineStarts, i+1)
+		}
+	}
+
+	// Only non-ASCII input needs a rune to byte table
+	if ascii {
+		idx.runeCount = len(text)
+	} else {
+		idx.runeCount = utf8.RuneCountInString(text)
+		idx.checkpoints = make([]int, 0, idx.runeCount/checkpointInterval+1)
+
+		runes := 0
+		for offset := range text {
+			if runes%checkpointInterval == 0 {
+				idx.checkpoints = append(idx.checkpoints, offset)
+			}
+			runes++
+		}
+	}
+
+	return idx
+}
+
+// Text returns the indexed script text
+func (x *Index) Text() string {
+	return x.text
+}
+
+// ByteOffset converts a rune index, as reported by ANTLR tokens, to a byte offset
+func (x *Index) ByteOffset(runeIndex int) int {
+	if runeIndex < 0 {
+		return 0
+	}
+	if runeIndex >= x.runeCount {
+		return len(x.text)
+	}
+	if x.checkpoints == nil {
+		return runeIndex
+	}
+
+	offset := x.checkpoints[runeIndex/checkpointInterval]
+	for i := runeIndex % checkpointInterval; i > 0; i-- {
+		_, size := utf8.DecodeRuneInString(x.text[offset:])
+		offset += size
+	}
+	return offset
+}
+
+// RuneCount returns the number of runes in the text
+func (x *Index) RuneCount() int {
+	return x.runeCount
+}
+
+// ASCII reports whether the text is pure ASCII, in which case rune indexes and
+// byte offsets are the same
+func (x *Index) ASCII() bool {
+	return x.checkpoints == nil
+}
+
+// LineColumn returns the 1-based line and 0-based rune column of a byte offset
+func (x *Index) LineColumn(offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(x.text) {
+		offset = len(x.text)
+	}
+
+	line := sort.Search(len(x.lineStarts), func(i int) bool {
+		return x.lineStarts[i] > offset
+	})
+	column := utf8.RuneCountInString(x.text[x.lineStarts[line-1]:offset])
+
+	return line, column
+}
+
+// Slice returns the text between two byte offsets, clamped to the script
+func (x *Index) Slice(start, end int) string {
+	if start < 0 {
+		start = 0
+	}
+	if end > len(x.text) {
+		end = len(x.text)
+	}
+	if start >= end {
+		return ""
+	}
+	return x.text[start:end]
+}
+
+// AloneOnLine reports whether only whitespace surrounds the byte range
+// [start, end) on its line
+func (x *Index) AloneOnLine(start, end int) bool {
+	lineStart := strings.LastIndexByte(x.text[:start], '\n') + 1
+	lineEnd := strings.IndexByte(x.text[end:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(x.text)
+	} else {
+		lineEnd += end
+	}
+
+	return strings.TrimSpace(x.text[lineStart:start]) == "" && strings.TrimSpace(x.text[end:lineEnd]) == ""
+}
@@ -0,0 +1,62 @@
+package source
+
+import "testing"
+
+func TestIndex_ASCII(t *testing.T) {
+	idx := NewIndex("SELECT 1\nFROM dual;\n")
+
+	if got := idx.ByteOffset(9); got != 9 {
+		t.Errorf("Expected byte offset 9, got %d", got)
+	}
+
+	line, column := idx.LineColumn(14)
+	if line != 2 || column != 5 {
+		t.Errorf("Expected 2:5, got %d:%d", line, column)
+	}
+
+	if got := idx.Slice(9, 13); got != "FROM" {
+		t.Errorf("Expected slice %q, got %q", "FROM", got)
+	}
+}
+
+func TestIndex_NonASCII(t *testing.T) {
+	// 'é' and 'ü' take two bytes each in UTF-8
+	text := "SELECT 'é'\nFROM dual WHERE ü = 1;"
+	idx := NewIndex(text)
+
+	// Rune 9 is the closing quote, which sits at byte 10
+	if got := idx.ByteOffset(9); got != 10 {
+		t.Errorf("Expected byte offset 10, got %d", got)
+	}
+
+	// Rune 27 is 'ü'
+	start := idx.ByteOffset(27)
+	if got := idx.Slice(start, idx.ByteOffset(28)); got != "ü" {
+		t.Errorf("Expected %q, got %q", "ü", got)
+	}
+
+	line, column := idx.LineColumn(start)
+	if line != 2 || column != 16 {
+		t.Errorf("Expected 2:16, got %d:%d", line, column)
+	}
+
+	// Out of range rune indexes clamp to the end of the text
+	if got := idx.ByteOffset(1000); got != len(text) {
+		t.Errorf("Expected byte offset %d, got %d", len(text), got)
+	}
+}
+
+func TestIndex_AloneOnLine(t *testing.T) {
+	text := "SELECT 4 / 2 FROM dual;\n  /  \n/"
+	idx := NewIndex(text)
+
+	if idx.AloneOnLine(9, 10) {
+		t.Errorf("Division operator should not be alone on its line")
+	}
+	if !idx.AloneOnLine(26, 27) {
+		t.Errorf("Indented slash should be alone on its line")
+	}
+	if !idx.AloneOnLine(len(text)-1, len(text)) {
+		t.Errorf("Slash on the last line should be alone on its line")
+	}
+}
@@ -0,0 +1,138 @@
+package source
+
+import (
+	"unicode/utf8"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// Stream is an antlr.CharStream that reads runes directly from the indexed
+// text. Unlike antlr.NewInputStream it does not copy the script into a rune
+// slice, and the token texts it returns are substrings of the script.
+type Stream struct {
+	index  *Index
+	pos    int // Rune index of the next character
+	offset int // Byte offset of pos
+}
+
+var _ antlr.CharStream = (*Stream)(nil)
+
+// NewStream creates a character stream over the indexed text
+func NewStream(index *Index) *Stream {
+	return &Stream{index: index}
+}
+
+// Consume moves to the next character
+func (s *Stream) Consume() {
+	if s.pos >= s.index.runeCount {
+		panic("cannot consume EOF")
+	}
+	s.pos++
+	if c := s.index.text[s.offset]; c < utf8.RuneSelf {
+		s.offset++
+		return
+	}
+	_, size := utf8.DecodeRuneInString(s.index.text[s.offset:])
+	s.offset += size
+}
+
+// LA returns the character at the given offset from the current position,
+// where 1 is the next character and -1 the previous one
+func (s *Stream) LA(offset int) int {
+	if offset == 0 {
+		return 0
+	}
+	if offset < 0 {
+		offset++ // LA(-1) is the character just before pos
+	}
+	target := s.pos + offset - 1
+	if target < 0 || target >= s.index.runeCount {
+		return antlr.TokenEOF
+	}
+
+	text := s.index.text
+	if s.index.ASCII() {
+		return int(text[target])
+	}
+	if target == s.pos {
+		// The next character, by far the most common lookahead
+		if c := text[s.offset]; c < utf8.RuneSelf {
+			return int(c)
+		}
+		r, _ := utf8.DecodeRuneInString(text[s.offset:])
+		return int(r)
+	}
+
+	// Walk from the current position, which is next to the target in practice
+	byteOffset := s.offset
+	for i := s.pos; i < target; i++ {
+		_, size := utf8.DecodeRuneInString(text[byteOffset:])
+		byteOffset += size
+	}
+	for i := s.pos; i > target; i-- {
+		_, size := utf8.DecodeLastRuneInString(text[:byteOffset])
+		byteOffset -= size
+	}
+	r, _ := utf8.DecodeRuneInString(text[byteOffset:])
+	return int(r)
+}
+
+// Mark does nothing as the whole text is available
+func (s *Stream) Mark() int {
+	return -1
+}
+
+// Release does nothing as the whole text is available
+func (s *Stream) Release(_ int) {
+}
+
+// Index returns the current rune index
+func (s *Stream) Index() int {
+	return s.pos
+}
+
+// Seek moves to the given rune index
+func (s *Stream) Seek(index int) {
+	if index > s.index.runeCount {
+		index = s.index.runeCount
+	}
+	if index < 0 {
+		index = 0
+	}
+	s.pos = index
+	s.offset = s.index.ByteOffset(index)
+}
+
+// Size returns the number of runes in the text
+func (s *Stream) Size() int {
+	return s.index.runeCount
+}
+
+// GetSourceName returns the name of the stream
+func (s *Stream) GetSourceName() string {
+	return "Obtained from string"
+}
+
+// GetText returns the text between two inclusive rune indexes
+func (s *Stream) GetText(start int, stop int) string {
+	return s.index.Slice(s.index.ByteOffset(start), s.index.ByteOffset(stop+1))
+}
+
+// GetTextFromTokens returns the text from the first character of start to the
+// last character of stop
+func (s *Stream) GetTextFromTokens(start, stop antlr.Token) string {
+	if start == nil || stop == nil {
+		return ""
+	}
+	return s.GetText(start.GetStart(), stop.GetStop())
+}
+
+// GetTextFromInterval returns the text of an inclusive rune interval
+func (s *Stream) GetTextFromInterval(i antlr.Interval) string {
+	return s.GetText(i.Start, i.Stop)
+}
+
+// String returns the whole text
+func (s *Stream) String() string {
+	return s.index.text
+}
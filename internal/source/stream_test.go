@@ -0,0 +1,106 @@
+package source
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// TestStream_MatchesInputStream drives both streams through the same
+// operations, as the lexer would
+func TestStream_MatchesInputStream(t *testing.T) {
+	for _, text := range []string{
+		"SELECT * FROM employees;",
+		"SELECT 'Zoë', 'naïve' FROM dual; -- ünïcödé\n/",
+		"",
+	} {
+		want := antlr.NewInputStream(text)
+		got := NewStream(NewIndex(text))
+
+		if got.Size() != want.Size() {
+			t.Fatalf("%q: expected size %d, got %d", text, want.Size(), got.Size())
+		}
+
+		for step := 0; step <= want.Size(); step++ {
+			for _, offset := range []int{-2, -1, 0, 1, 2, 3} {
+				if g, w := got.LA(offset), want.LA(offset); g != w {
+					t.Fatalf("%q: LA(%d) at %d: expected %d, got %d", text, offset, step, w, g)
+				}
+			}
+			if got.Index() != want.Index() {
+				t.Fatalf("%q: expected index %d, got %d", text, want.Index(), got.Index())
+			}
+			if step < want.Size() {
+				got.Consume()
+				want.Consume()
+			}
+		}
+
+		// Seeking backwards and forwards, as the lexer does after a failed match
+		for _, index := range []int{0, want.Size() / 2, 3, want.Size()} {
+			got.Seek(index)
+			want.Seek(index)
+			if got.Index() != want.Index() || got.LA(1) != want.LA(1) {
+				t.Errorf("%q: seek to %d: expected %d/%d, got %d/%d", text, index, want.Index(), want.LA(1), got.Index(), got.LA(1))
+			}
+		}
+
+		if text != "" {
+			if g, w := got.GetText(7, 12), want.GetText(7, 12); g != w {
+				t.Errorf("%q: expected text %q, got %q", text, w, g)
+			}
+		}
+	}
+}
+
+func TestIndex_Checkpoints(t *testing.T) {
+	// Enough non-ASCII runes to need several checkpoints
+	text := ""
+	for i := 0; i < 200; i++ {
+		text += "é"
+	}
+	idx := NewIndex(text)
+
+	if idx.ASCII() {
+		t.Fatalf("Expected non-ASCII index")
+	}
+	if idx.RuneCount() != 200 {
+		t.Errorf("Expected 200 runes, got %d", idx.RuneCount())
+	}
+	for _, runeIndex := range []int{0, 63, 64, 65, 130, 199, 200} {
+		if got := idx.ByteOffset(runeIndex); got != runeIndex*2 {
+			t.Errorf("Rune %d: expected byte offset %d, got %d", runeIndex, runeIndex*2, got)
+		}
+	}
+}
+
+// benchmarkText is a large script with a few non-ASCII characters
+var benchmarkText = strings.Repeat("INSERT INTO customers (full_name, city) VALUES ('Zoë Müller', 'Zürich');\n", 50000)
+
+// BenchmarkStream_Consume reads the script through Stream
+func BenchmarkStream_Consume(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkText)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		stream := NewStream(NewIndex(benchmarkText))
+		for stream.LA(1) != antlr.TokenEOF {
+			stream.Consume()
+		}
+	}
+}
+
+// BenchmarkInputStream_Consume reads the script through antlr.InputStream,
+// which copies it into a rune slice first
+func BenchmarkInputStream_Consume(b *testing.B) {
+	b.SetBytes(int64(len(benchmarkText)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		stream := antlr.NewInputStream(benchmarkText)
+		for stream.LA(1) != antlr.TokenEOF {
+			stream.Consume()
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package ast provides read-only access to the parse tree built for each
+// statement returned by the splitter.
+//
+// Every Node wraps a context of the ANTLR parse tree produced from
+// PlSqlParser.g4. Rule nodes report the grammar rule name (for example
+// "create_procedure_body" or "select_statement"), token nodes report the
+// symbolic token name from PlSqlLexer.g4 (for example "REGULAR_ID" or
+// "SEMICOLON"), and every node carries its source span and exact text.
+//
+// # Compatibility
+//
+// The Node interface, the Kind, Position and Span types and the helper
+// functions in this package are covered by the module's semantic versioning
+// guarantees: they will not change incompatibly within a major version.
+//
+// Rule and token names come directly from the grammar. Grammar updates may
+// add rules or restructure the children of existing ones; renaming or removing
+// a rule or token is treated as a breaking change and is listed in the release
+// notes. Code that matches on names should tolerate unknown children.
+package ast
+
+// Kind identifies what a Node represents
+type Kind int
+
+const (
+	// KindRule is a grammar rule such as select_statement
+	KindRule Kind = iota
+	// KindToken is a token matched by the parser
+	KindToken
+	// KindError is a token the parser could not match during error recovery
+	KindError
+)
+
+// String returns the string representation of a Kind
+func (k Kind) String() string {
+	switch k {
+	case KindRule:
+		return "RULE"
+	case KindToken:
+		return "TOKEN"
+	case KindError:
+		return "ERROR"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// Position is a location in the script text
+type Position struct {
+	Offset int `json:"offset"` // Byte offset from the start of the script
+	Line   int `json:"line"`   // 1-based line number
+	Column int `json:"column"` // 0-based column, counted in characters
+}
+
+// Span is a half-open range [Start, End) of the script text
+type Span struct {
+	Start Position `json:"start"`
+	End   Position `json:"end"`
+}
+
+// Len returns the length of the span in bytes
+func (s Span) Len() int {
+	return s.End.Offset - s.Start.Offset
+}
+
+// Node is a read-only view of a parse tree node
+type Node interface {
+	// Kind reports whether the node is a rule, a token or an error token
+	Kind() Kind
+	// RuleName returns the grammar rule name, or "" for token nodes
+	RuleName() string
+	// TokenName returns the symbolic token name, or "" for rule nodes
+	TokenName() string
+	// Text returns the exact source text of the node, including comments and whitespace
+	Text() string
+	// Span returns the source span of the node
+	Span() Span
+	// Parent returns the enclosing node, or nil at the root of a statement
+	Parent() Node
+	// ChildCount returns the number of direct children
+	ChildCount() int
+	// Child returns the i-th direct child, or nil if i is out of range
+	Child(i int) Node
+	// Children returns all direct children
+	Children() []Node
+}
+
+// Walk traverses the tree rooted at n in depth-first order. Children of a node
+// are skipped when fn returns false for it.
+func Walk(n Node, fn func(Node) bool) {
+	if n == nil || !fn(n) {
+		return
+	}
+	for i := 0; i < n.ChildCount(); i++ {
+		Walk(n.Child(i), fn)
+	}
+}
+
+// Find returns every rule node below n (including n) with the given rule name
+func Find(n Node, ruleName string) []Node {
+	var found []Node
+	Walk(n, func(node Node) bool {
+		if node.Kind() == KindRule && node.RuleName() == ruleName {
+			found = append(found, node)
+		}
+		return true
+	})
+	return found
+}
+
+// FirstToken returns the first token node below n, or nil if there is none
+func FirstToken(n Node) Node {
+	var first Node
+	Walk(n, func(node Node) bool {
+		if first != nil {
+			return false
+		}
+		if node.Kind() != KindRule {
+			first = node
+			return false
+		}
+		return true
+	})
+	return first
+}
@@ -0,0 +1,90 @@
+package ast_test
+
+import (
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/splitter"
+)
+
+func TestStatementNode(t *testing.T) {
+	input := `SELECT id FROM employees;
+CREATE OR REPLACE PROCEDURE hello_world IS
+BEGIN
+    DBMS_OUTPUT.PUT_LINE('Héllo');
+END;
+/`
+
+	s := splitter.NewSplitter(splitter.WithParseTree(true))
+	statements, err := s.SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if len(statements) < 2 {
+		t.Fatalf("Expected at least 2 statements, got %d", len(statements))
+	}
+
+	for i, stmt := range statements {
+		node := stmt.Node()
+		if node == nil {
+			t.Fatalf("Statement %d has no parse tree", i)
+		}
+		if node.Kind() != ast.KindRule || node.RuleName() == "" {
+			t.Errorf("Statement %d root should be a named rule, got %s %q", i, node.Kind(), node.RuleName())
+		}
+		if node.Parent() != nil {
+			t.Errorf("Statement %d root should have no parent", i)
+		}
+		if node.Text() != stmt.Content {
+			t.Errorf("Statement %d: expected node text %q, got %q", i, stmt.Content, node.Text())
+		}
+		if node.Span().Start.Line != stmt.StartLine || node.Span().Start.Column != stmt.StartColumn {
+			t.Errorf("Statement %d: span %+v does not match statement position %d:%d",
+				i, node.Span(), stmt.StartLine, stmt.StartColumn)
+		}
+	}
+
+	// The procedure body contains a call whose string literal has a multi-byte character
+	procedure := statements[1].Node()
+	if len(ast.Find(procedure, "create_procedure_body")) != 1 {
+		t.Errorf("Expected to find create_procedure_body in %q", procedure.Text())
+	}
+
+	var literal ast.Node
+	ast.Walk(procedure, func(n ast.Node) bool {
+		if n.Kind() == ast.KindToken && n.TokenName() == "CHAR_STRING" {
+			literal = n
+		}
+		return true
+	})
+	if literal == nil {
+		t.Fatalf("Expected a CHAR_STRING token in the procedure")
+	}
+	if literal.Text() != "'Héllo'" {
+		t.Errorf("Expected literal text %q, got %q", "'Héllo'", literal.Text())
+	}
+	if literal.Span().Len() != len("'Héllo'") {
+		t.Errorf("Expected literal span of %d bytes, got %d", len("'Héllo'"), literal.Span().Len())
+	}
+	if literal.Span().Start.Line != 4 {
+		t.Errorf("Expected literal on line 4, got %d", literal.Span().Start.Line)
+	}
+	if literal.Parent() == nil {
+		t.Errorf("Expected literal to have a parent")
+	}
+
+	first := ast.FirstToken(procedure)
+	if first == nil || first.TokenName() != "CREATE" {
+		t.Errorf("Expected first token CREATE, got %v", first)
+	}
+}
+
+func TestStatementNode_Disabled(t *testing.T) {
+	statements, err := splitter.SplitString("SELECT * FROM employees;")
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if statements[0].Node() != nil {
+		t.Errorf("Expected no parse tree without WithParseTree(true)")
+	}
+}
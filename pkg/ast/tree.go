@@ -0,0 +1,166 @@
+package ast
+
+import (
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+)
+
+// Tree holds the state shared by all nodes of one parsed script
+type Tree struct {
+	index      *source.Index
+	ruleNames  []string
+	tokenNames []string
+}
+
+// NewTree creates a Tree for the given script text. It is used by the
+// splitter to wrap the parse tree it builds; most callers get nodes from
+// Statement.Node instead.
+func NewTree(text string) *Tree {
+	gen.PlSqlParserInit()
+	gen.PlSqlLexerInit()
+
+	return &Tree{
+		index:      source.NewIndex(text),
+		ruleNames:  gen.PlSqlParserParserStaticData.RuleNames,
+		tokenNames: gen.PlSqlLexerLexerStaticData.SymbolicNames,
+	}
+}
+
+// Node wraps an ANTLR parse tree context. The returned node is the root of its
+// own view: its Parent is nil.
+func (t *Tree) Node(pt antlr.Tree) Node {
+	if pt == nil {
+		return nil
+	}
+	return &node{tree: t, pt: pt, root: pt}
+}
+
+// ScriptNode wraps an ANTLR parse tree context whose Parent chain extends up
+// to the sql_script root of the whole script
+func (t *Tree) ScriptNode(pt antlr.Tree) Node {
+	if pt == nil {
+		return nil
+	}
+	return &node{tree: t, pt: pt}
+}
+
+// node implements Node on top of an antlr.Tree
+type node struct {
+	tree *Tree
+	pt   antlr.Tree
+	root antlr.Tree // Ancestors above root are not exposed; nil for no limit
+}
+
+func (n *node) Kind() Kind {
+	switch n.pt.(type) {
+	case antlr.ErrorNode:
+		return KindError
+	case antlr.TerminalNode:
+		return KindToken
+	default:
+		return KindRule
+	}
+}
+
+func (n *node) RuleName() string {
+	ctx, ok := n.pt.(antlr.RuleContext)
+	if !ok {
+		return ""
+	}
+	index := ctx.GetRuleIndex()
+	if index < 0 || index >= len(n.tree.ruleNames) {
+		return ""
+	}
+	return n.tree.ruleNames[index]
+}
+
+func (n *node) TokenName() string {
+	terminal, ok := n.pt.(antlr.TerminalNode)
+	if !ok {
+		return ""
+	}
+	tokenType := terminal.GetSymbol().GetTokenType()
+	if tokenType == antlr.TokenEOF {
+		return "EOF"
+	}
+	if tokenType < 0 || tokenType >= len(n.tree.tokenNames) {
+		return ""
+	}
+	return n.tree.tokenNames[tokenType]
+}
+
+func (n *node) Text() string {
+	span := n.Span()
+	return n.tree.index.Slice(span.Start.Offset, span.End.Offset)
+}
+
+func (n *node) Span() Span {
+	start, stop := n.tokens()
+	if start == nil {
+		return Span{}
+	}
+
+	// Rules that matched nothing have their stop token before their start token
+	startOffset := n.tree.index.ByteOffset(start.GetStart())
+	endOffset := startOffset
+	if stop != nil && stop.GetStop() >= start.GetStart() {
+		endOffset = n.tree.index.ByteOffset(stop.GetStop() + 1)
+	}
+
+	return Span{
+		Start: n.position(startOffset),
+		End:   n.position(endOffset),
+	}
+}
+
+// tokens returns the first and last token covered by the node
+func (n *node) tokens() (antlr.Token, antlr.Token) {
+	switch pt := n.pt.(type) {
+	case antlr.TerminalNode:
+		symbol := pt.GetSymbol()
+		if symbol.GetTokenType() == antlr.TokenEOF {
+			return symbol, nil
+		}
+		return symbol, symbol
+	case antlr.ParserRuleContext:
+		return pt.GetStart(), pt.GetStop()
+	default:
+		return nil, nil
+	}
+}
+
+func (n *node) position(offset int) Position {
+	line, column := n.tree.index.LineColumn(offset)
+	return Position{Offset: offset, Line: line, Column: column}
+}
+
+func (n *node) Parent() Node {
+	if n.pt == n.root {
+		return nil
+	}
+	parent := n.pt.GetParent()
+	if parent == nil {
+		return nil
+	}
+	return &node{tree: n.tree, pt: parent, root: n.root}
+}
+
+func (n *node) ChildCount() int {
+	return n.pt.GetChildCount()
+}
+
+func (n *node) Child(i int) Node {
+	if i < 0 || i >= n.pt.GetChildCount() {
+		return nil
+	}
+	return &node{tree: n.tree, pt: n.pt.GetChild(i), root: n.root}
+}
+
+func (n *node) Children() []Node {
+	children := make([]Node, n.pt.GetChildCount())
+	for i := range children {
+		children[i] = n.Child(i)
+	}
+	return children
+}
@@ -0,0 +1,138 @@
+package lexer
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+)
+
+// Kind is the category of a token
+type Kind int
+
+const (
+	KindOther            Kind = iota // Tokens that fit no other category
+	KindKeyword                      // Reserved and non-reserved keywords such as SELECT or ROWNUM
+	KindIdentifier                   // Unquoted identifiers
+	KindQuotedIdentifier             // Double-quoted identifiers
+	KindString                       // Character, national, bit and hex string literals
+	KindNumber                       // Integer and decimal literals
+	KindComment                      // Single-line, multi-line and REMARK comments
+	KindBindVariable                 // Bind variables such as :name or :1
+	KindOperator                     // Operators and punctuation
+	KindSQLPlus                      // SQL*Plus commands such as PROMPT, @script and a lone /
+	KindWhitespace                   // Spaces, tabs and newlines
+	KindDirective                    // Conditional compilation directives such as $IF and $$PLSQL_UNIT
+	KindOpaque                       // Payloads of wrapped units and Java sources, which are not PL/SQL
+)
+
+var kindNames = map[Kind]string{
+	KindOther:            "OTHER",
+	KindKeyword:          "KEYWORD",
+	KindIdentifier:       "IDENTIFIER",
+	KindQuotedIdentifier: "QUOTED_IDENTIFIER",
+	KindString:           "STRING",
+	KindNumber:           "NUMBER",
+	KindComment:          "COMMENT",
+	KindBindVariable:     "BIND_VARIABLE",
+	KindOperator:         "OPERATOR",
+	KindSQLPlus:          "SQLPLUS",
+	KindWhitespace:       "WHITESPACE",
+	KindDirective:        "DIRECTIVE",
+	KindOpaque:           "OPAQUE",
+}
+
+// String returns the string representation of a Kind
+func (k Kind) String() string {
+	if name, ok := kindNames[k]; ok {
+		return name
+	}
+	return "OTHER"
+}
+
+// MarshalJSON marshals a Kind to JSON
+func (k Kind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(k.String())
+}
+
+// in reports whether k is one of kinds
+func (k Kind) in(kinds []Kind) bool {
+	for _, kind := range kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// kindOf categorises a token type
+func kindOf(tokenType int) Kind {
+	switch tokenType {
+	case gen.PlSqlLexerREGULAR_ID:
+		return KindIdentifier
+	case gen.PlSqlLexerDELIMITED_ID:
+		return KindQuotedIdentifier
+	case gen.PlSqlLexerCHAR_STRING, gen.PlSqlLexerNATIONAL_CHAR_STRING_LIT,
+		gen.PlSqlLexerBIT_STRING_LIT, gen.PlSqlLexerHEX_STRING_LIT:
+		return KindString
+	case gen.PlSqlLexerUNSIGNED_INTEGER, gen.PlSqlLexerAPPROXIMATE_NUM_LIT:
+		return KindNumber
+	case gen.PlSqlLexerSINGLE_LINE_COMMENT, gen.PlSqlLexerMULTI_LINE_COMMENT, gen.PlSqlLexerREMARK_COMMENT:
+		return KindComment
+	case gen.PlSqlLexerBINDVAR:
+		return KindBindVariable
+	case gen.PlSqlLexerPROMPT_MESSAGE, gen.PlSqlLexerSTART_CMD:
+		return KindSQLPlus
+	case gen.PlSqlLexerSPACES:
+		return KindWhitespace
+	case gen.PlSqlLexerCC_IF, gen.PlSqlLexerCC_THEN, gen.PlSqlLexerCC_ELSIF, gen.PlSqlLexerCC_ELSE,
+		gen.PlSqlLexerCC_END, gen.PlSqlLexerCC_ERROR, gen.PlSqlLexerINQUIRY_DIRECTIVE:
+		return KindDirective
+	case gen.PlSqlLexerOPAQUE_BODY:
+		return KindOpaque
+	}
+
+	literal := literalName(tokenType)
+	switch {
+	case literal == "":
+		return KindOther
+	case isWord(literal):
+		return KindKeyword
+	default:
+		return KindOperator
+	}
+}
+
+// literalName returns the literal text of a fixed token, such as SELECT or :=
+func literalName(tokenType int) string {
+	gen.PlSqlLexerInit()
+	names := gen.PlSqlLexerLexerStaticData.LiteralNames
+	if tokenType <= 0 || tokenType >= len(names) {
+		return ""
+	}
+	return strings.Trim(names[tokenType], "'")
+}
+
+// tokenName returns the symbolic name of a token type
+func tokenName(tokenType int) string {
+	gen.PlSqlLexerInit()
+	names := gen.PlSqlLexerLexerStaticData.SymbolicNames
+	if tokenType <= 0 || tokenType >= len(names) {
+		return ""
+	}
+	return names[tokenType]
+}
+
+// isWord reports whether a literal is made of identifier characters
+func isWord(literal string) bool {
+	for i, r := range literal {
+		isLetter := (r >= 'A' && r <= 'Z') || (r >= 'a' && r <= 'z')
+		if i == 0 && !isLetter {
+			return false
+		}
+		if !isLetter && !(r >= '0' && r <= '9') && r != '_' && r != '$' && r != '#' {
+			return false
+		}
+	}
+	return literal != ""
+}
@@ -0,0 +1,135 @@
+// Package lexer exposes the PL/SQL tokenizer used by the splitter without
+// running the parser. It is intended for lightweight tooling such as syntax
+// highlighting, identifier search-and-replace and token counting.
+//
+// Tokens are produced lazily, so very large scripts can be processed without
+// holding the whole token list in memory.
+package lexer
+
+import (
+	"iter"
+
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+	"github.com/zodimo/go-plsql-statement-splitter/internal/source"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+)
+
+// Channel identifies the token channel
+type Channel int
+
+const (
+	// ChannelDefault carries tokens seen by the parser
+	ChannelDefault Channel = antlr.TokenDefaultChannel
+	// ChannelHidden carries whitespace and comments
+	ChannelHidden Channel = antlr.TokenHiddenChannel
+)
+
+// Token is a single lexical token
+type Token struct {
+	Kind    Kind     `json:"kind"`
+	Name    string   `json:"name"`    // Symbolic token name from PlSqlLexer.g4, such as REGULAR_ID
+	Type    int      `json:"type"`    // Numeric token type
+	Text    string   `json:"text"`    // Exact source text of the token
+	Channel Channel  `json:"channel"` // Channel the token was emitted on
+	Span    ast.Span `json:"span"`    // Byte, line and column span of the token
+}
+
+// Lexer produces tokens from a script
+type Lexer struct {
+	lexer *gen.PlSqlLexer
+	index *source.Index
+	done  bool
+}
+
+// New creates a lexer for the given script text
+func New(text string) *Lexer {
+	index := source.NewIndex(text)
+	lexer := gen.NewPlSqlLexer(source.NewStream(index))
+	// Unrecognised characters are skipped instead of being printed to stderr
+	lexer.RemoveErrorListeners()
+
+	return &Lexer{
+		lexer: lexer,
+		index: index,
+	}
+}
+
+// Next returns the next token. The second result is false once the end of the
+// input has been reached.
+func (l *Lexer) Next() (Token, bool) {
+	if l.done {
+		return Token{}, false
+	}
+
+	t := l.lexer.NextToken()
+	if t.GetTokenType() == antlr.TokenEOF {
+		l.done = true
+		return Token{}, false
+	}
+
+	return l.token(t), true
+}
+
+// All returns an iterator over the remaining tokens
+func (l *Lexer) All() iter.Seq[Token] {
+	return func(yield func(Token) bool) {
+		for {
+			t, ok := l.Next()
+			if !ok || !yield(t) {
+				return
+			}
+		}
+	}
+}
+
+// Tokenize returns every token of the script, including hidden tokens
+func Tokenize(text string) []Token {
+	var tokens []Token
+	for t := range New(text).All() {
+		tokens = append(tokens, t)
+	}
+	return tokens
+}
+
+// Count returns the number of tokens of the given kinds, or of all kinds when
+// none are given, without keeping the tokens in memory
+func Count(text string, kinds ...Kind) int {
+	count := 0
+	for t := range New(text).All() {
+		if len(kinds) == 0 || t.Kind.in(kinds) {
+			count++
+		}
+	}
+	return count
+}
+
+// token converts an ANTLR token into a Token
+func (l *Lexer) token(t antlr.Token) Token {
+	start := l.index.ByteOffset(t.GetStart())
+	end := l.index.ByteOffset(t.GetStop() + 1)
+	text := l.index.Slice(start, end)
+
+	kind := kindOf(t.GetTokenType())
+	if kind == KindOperator && t.GetTokenType() == gen.PlSqlLexerSOLIDUS && l.index.AloneOnLine(start, end) {
+		// A slash on a line of its own is the SQL*Plus command that runs the buffer
+		kind = KindSQLPlus
+	}
+
+	return Token{
+		Kind:    kind,
+		Name:    tokenName(t.GetTokenType()),
+		Type:    t.GetTokenType(),
+		Text:    text,
+		Channel: Channel(t.GetChannel()),
+		Span: ast.Span{
+			Start: l.position(start),
+			End:   l.position(end),
+		},
+	}
+}
+
+func (l *Lexer) position(offset int) ast.Position {
+	line, column := l.index.LineColumn(offset)
+	return ast.Position{Offset: offset, Line: line, Column: column}
+}
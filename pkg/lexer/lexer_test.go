@@ -0,0 +1,163 @@
+package lexer
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize_Kinds(t *testing.T) {
+	input := `-- load data
+PROMPT Loading
+SELECT "Mixed Case", emp_name, 'it''s', 42, 1.5e3 FROM employees WHERE id = :emp_id;
+/`
+
+	expected := []struct {
+		text string
+		kind Kind
+	}{
+		{"-- load data\n", KindComment},
+		{"PROMPT Loading\n", KindSQLPlus},
+		{"SELECT", KindKeyword},
+		{`"Mixed Case"`, KindQuotedIdentifier},
+		{",", KindOperator},
+		{"emp_name", KindIdentifier},
+		{"'it''s'", KindString},
+		{"42", KindNumber},
+		{"1.5e3", KindNumber},
+		{"FROM", KindKeyword},
+		{"=", KindOperator},
+		{":emp_id", KindBindVariable},
+		{";", KindOperator},
+		{"/", KindSQLPlus},
+	}
+
+	tokens := Tokenize(input)
+
+	// Index the non-whitespace tokens by text for lookup
+	found := make(map[string]Token)
+	for _, tok := range tokens {
+		if tok.Kind == KindWhitespace {
+			if tok.Channel != ChannelHidden {
+				t.Errorf("Whitespace token %q should be on the hidden channel", tok.Text)
+			}
+			continue
+		}
+		if _, ok := found[tok.Text]; !ok {
+			found[tok.Text] = tok
+		}
+	}
+
+	for _, e := range expected {
+		tok, ok := found[e.text]
+		if !ok {
+			t.Errorf("Token %q not found", e.text)
+			continue
+		}
+		if tok.Kind != e.kind {
+			t.Errorf("Token %q: expected kind %s, got %s (%s)", e.text, e.kind, tok.Kind, tok.Name)
+		}
+	}
+}
+
+func TestTokenize_Directives(t *testing.T) {
+	input := "$IF $$debug $THEN trace; $ELSIF $$level > 1 $THEN NULL; $ELSE $ERROR 'no' $END $END"
+
+	var directives []string
+	for _, tok := range Tokenize(input) {
+		if tok.Kind == KindDirective {
+			directives = append(directives, tok.Text)
+		}
+	}
+
+	want := []string{"$IF", "$$debug", "$THEN", "$ELSIF", "$$level", "$THEN", "$ELSE", "$ERROR", "$END", "$END"}
+	if strings.Join(directives, " ") != strings.Join(want, " ") {
+		t.Errorf("Expected directives %v, got %v", want, directives)
+	}
+}
+
+func TestTokenize_OpaqueBody(t *testing.T) {
+	input := "CREATE AND COMPILE JAVA SOURCE NAMED hello AS\nclass Hello { String s = \"it's\"; }\n/\n"
+
+	var opaque []Token
+	for _, tok := range Tokenize(input) {
+		if tok.Kind == KindOpaque {
+			opaque = append(opaque, tok)
+		}
+	}
+
+	if len(opaque) != 1 || opaque[0].Text != "class Hello { String s = \"it's\"; }" {
+		t.Errorf("Expected the Java source as one token, got %+v", opaque)
+	}
+}
+
+func TestTokenize_Spans(t *testing.T) {
+	input := "SELECT 'é'\n  FROM dual"
+	tokens := Tokenize(input)
+
+	// Tokens must cover the input exactly, in order
+	offset := 0
+	for _, tok := range tokens {
+		if tok.Span.Start.Offset != offset {
+			t.Fatalf("Token %q starts at %d, expected %d", tok.Text, tok.Span.Start.Offset, offset)
+		}
+		if input[tok.Span.Start.Offset:tok.Span.End.Offset] != tok.Text {
+			t.Errorf("Token text %q does not match its span", tok.Text)
+		}
+		offset = tok.Span.End.Offset
+	}
+	if offset != len(input) {
+		t.Errorf("Tokens end at %d, expected %d", offset, len(input))
+	}
+
+	for _, tok := range tokens {
+		if tok.Text == "FROM" {
+			if tok.Span.Start.Line != 2 || tok.Span.Start.Column != 2 {
+				t.Errorf("Expected FROM at 2:2, got %d:%d", tok.Span.Start.Line, tok.Span.Start.Column)
+			}
+		}
+	}
+}
+
+func TestSlashInsideExpression(t *testing.T) {
+	for _, tok := range Tokenize("SELECT a / b FROM t") {
+		if tok.Text == "/" && tok.Kind != KindOperator {
+			t.Errorf("Division should be an operator, got %s", tok.Kind)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	input := "SELECT col1, col2 FROM tab1; -- done"
+
+	if got := Count(input, KindIdentifier); got != 3 {
+		t.Errorf("Expected 3 identifiers, got %d", got)
+	}
+	if got := Count(input, KindComment); got != 1 {
+		t.Errorf("Expected 1 comment, got %d", got)
+	}
+	if got := Count(input); got != len(Tokenize(input)) {
+		t.Errorf("Count without kinds should count every token")
+	}
+}
+
+func TestLexer_Next(t *testing.T) {
+	l := New("COMMIT;")
+
+	var names []string
+	for {
+		tok, ok := l.Next()
+		if !ok {
+			break
+		}
+		names = append(names, tok.Name)
+	}
+
+	if len(names) != 2 || names[0] != "COMMIT" || names[1] != "SEMICOLON" {
+		t.Errorf("Expected [COMMIT SEMICOLON], got %v", names)
+	}
+
+	// Once exhausted the lexer keeps reporting the end of input
+	if _, ok := l.Next(); ok {
+		t.Errorf("Expected no more tokens")
+	}
+}
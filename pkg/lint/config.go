@@ -0,0 +1,51 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Config enables, disables and sets the severity of rules. Its JSON form maps
+// rule names to severities:
+//
+//	{
+//	  "rules": {
+//	    "select-star-in-view": "error",
+//	    "drop-table-without-purge": "off"
+//	  }
+//	}
+//
+// Rules that are not listed keep their default severity.
+type Config struct {
+	Rules map[string]Severity `json:"rules"`
+}
+
+// LoadConfig reads a configuration from a JSON file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading lint config: %w", err)
+	}
+
+	config, err := ParseConfig(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// ParseConfig reads a configuration from JSON
+func ParseConfig(data []byte) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid lint config: %w", err)
+	}
+
+	for name, severity := range config.Rules {
+		if !severity.valid() {
+			return nil, fmt.Errorf("invalid lint config: rule %s has severity %q, expected off, info, warning or error", name, severity)
+		}
+	}
+	return &config, nil
+}
@@ -0,0 +1,40 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lint.json")
+	if err := os.WriteFile(path, []byte(`{"rules": {"select-star-in-view": "error", "drop-table-without-purge": "off"}}`), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+
+	config, err := LoadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if config.Rules[RuleSelectStarInView] != SeverityError || config.Rules[RuleDropTableWithoutPurge] != SeverityOff {
+		t.Errorf("Unexpected rules %v", config.Rules)
+	}
+}
+
+func TestParseConfig_Invalid(t *testing.T) {
+	tests := []struct {
+		input   string
+		message string
+	}{
+		{`{"rules": {"grant-to-public": "fatal"}}`, `rule grant-to-public has severity "fatal"`},
+		{`{"rules": ["grant-to-public"]}`, "invalid lint config"},
+	}
+
+	for _, tc := range tests {
+		_, err := ParseConfig([]byte(tc.input))
+		if err == nil || !strings.Contains(err.Error(), tc.message) {
+			t.Errorf("Expected an error containing %q for %s, got %v", tc.message, tc.input, err)
+		}
+	}
+}
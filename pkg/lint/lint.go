@@ -0,0 +1,263 @@
+// Package lint checks the statements of PL/SQL scripts against rules, such as
+// DELETE statements without a WHERE clause or exception handlers that swallow
+// every error.
+//
+// A Linter splits a script with the splitter, keeping the parse tree of each
+// statement, and hands every statement to every enabled Rule. The built-in
+// rules are listed by BuiltinRules; custom rules are added with WithRules.
+// Rules are enabled, disabled and given a severity through a Config, usually
+// read from a JSON file with LoadConfig, and findings are suppressed in the
+// script itself with comments:
+//
+//	-- lint:disable-next-line delete-without-where
+//	DELETE FROM staging_orders;
+//
+//	DROP TABLE tmp_load; -- lint:disable-line drop-table-without-purge
+//
+//	-- lint:disable when-others-null
+//	...
+//	-- lint:enable when-others-null
+//
+// A directive without rule names applies to every rule.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/splitter"
+)
+
+// Severity tells how serious a finding is
+type Severity string
+
+const (
+	SeverityOff     Severity = "off"     // The rule is disabled
+	SeverityInfo    Severity = "info"    // A matter of style
+	SeverityWarning Severity = "warning" // Likely to cause problems
+	SeverityError   Severity = "error"   // Should not be deployed
+)
+
+// valid reports whether s is one of the known severities
+func (s Severity) valid() bool {
+	switch s {
+	case SeverityOff, SeverityInfo, SeverityWarning, SeverityError:
+		return true
+	}
+	return false
+}
+
+// ErrUnknownRule is returned when a configuration names a rule that the
+// Linter does not have
+var ErrUnknownRule = errors.New("unknown lint rule")
+
+// Rule checks statements for one kind of problem
+type Rule interface {
+	// Name identifies the rule in configurations and suppression comments,
+	// such as delete-without-where
+	Name() string
+	// Description explains what the rule looks for
+	Description() string
+	// DefaultSeverity is the severity of the findings unless configured
+	// otherwise, SeverityOff for rules that are disabled by default
+	DefaultSeverity() Severity
+	// Check reports the problems of a statement. tree is the parse tree of
+	// the statement, or nil when it has none, as for wrapped units.
+	Check(stmt splitter.Statement, tree ast.Node, report ReportFunc)
+}
+
+// ReportFunc records a problem found by a Rule at a node of the parse tree.
+// A nil node reports the problem at the start of the statement.
+type ReportFunc func(node ast.Node, message string)
+
+// Finding is a problem reported by a Rule
+type Finding struct {
+	Rule           string   `json:"rule"`           // Name of the rule
+	Severity       Severity `json:"severity"`       // Configured severity of the rule
+	Message        string   `json:"message"`        // Description of the problem
+	Line           int      `json:"line"`           // 1-based line of the problem
+	Column         int      `json:"column"`         // 0-based column of the problem
+	StartOffset    int      `json:"startOffset"`    // Byte offset of the problem in the script
+	EndOffset      int      `json:"endOffset"`      // Byte offset just past the problem
+	StatementIndex int      `json:"statementIndex"` // Index of the statement in the script
+	File           string   `json:"file,omitempty"` // File the script was read from, if any
+}
+
+// String formats the finding as file:line:column: severity: message (rule)
+func (f Finding) String() string {
+	position := fmt.Sprintf("%d:%d", f.Line, f.Column)
+	if f.File != "" {
+		position = f.File + ":" + position
+	}
+	return fmt.Sprintf("%s: %s: %s (%s)", position, f.Severity, f.Message, f.Rule)
+}
+
+// Linter checks scripts against a set of rules.
+//
+// A Linter is safe for concurrent use by multiple goroutines if its rules are.
+type Linter struct {
+	rules           []Rule
+	config          *Config
+	splitterOptions []splitter.Option
+	splitter        *splitter.Splitter
+}
+
+// Option represents a configuration option for the Linter
+type Option func(*Linter)
+
+// NewLinter creates a Linter with the built-in rules and the provided options
+func NewLinter(options ...Option) *Linter {
+	l := &Linter{rules: BuiltinRules()}
+
+	for _, option := range options {
+		option(l)
+	}
+
+	// The rules need the parse tree of each statement
+	l.splitter = splitter.NewSplitter(append(l.splitterOptions, splitter.WithParseTree(true))...)
+	return l
+}
+
+// WithRules adds custom rules to the built-in ones. A custom rule replaces
+// the rule with the same name.
+func WithRules(rules ...Rule) Option {
+	return func(l *Linter) {
+		for _, rule := range rules {
+			replaced := false
+			for i, existing := range l.rules {
+				if existing.Name() == rule.Name() {
+					l.rules[i], replaced = rule, true
+				}
+			}
+			if !replaced {
+				l.rules = append(l.rules, rule)
+			}
+		}
+	}
+}
+
+// WithConfig configures which rules are enabled and their severities
+func WithConfig(config *Config) Option {
+	return func(l *Linter) {
+		l.config = config
+	}
+}
+
+// WithSplitterOptions configures how scripts are split, for example for the
+// Oracle version or SQL*Plus settings they target
+func WithSplitterOptions(options ...splitter.Option) Option {
+	return func(l *Linter) {
+		l.splitterOptions = append(l.splitterOptions, options...)
+	}
+}
+
+// Rules returns the rules of the Linter, the built-in ones first
+func (l *Linter) Rules() []Rule {
+	return append([]Rule(nil), l.rules...)
+}
+
+// Severity returns the configured severity of a rule, SeverityOff when it is
+// disabled
+func (l *Linter) Severity(rule Rule) Severity {
+	if l.config != nil {
+		if severity, ok := l.config.Rules[rule.Name()]; ok {
+			return severity
+		}
+	}
+	return rule.DefaultSeverity()
+}
+
+// LintFile checks a PL/SQL script file
+func (l *Linter) LintFile(path string) ([]Finding, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", splitter.ErrReadFile, err)
+	}
+
+	findings, err := l.LintString(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for i := range findings {
+		findings[i].File = path
+	}
+	return findings, nil
+}
+
+// LintString checks a PL/SQL script and returns the findings in script order.
+// Scripts with syntax errors cannot be checked and return the error of the
+// splitter.
+func (l *Linter) LintString(content string) ([]Finding, error) {
+	if err := l.checkConfig(); err != nil {
+		return nil, err
+	}
+
+	statements, err := l.splitter.SplitString(content)
+	if err != nil {
+		return nil, err
+	}
+
+	suppressions := parseSuppressions(content)
+	var findings []Finding
+	for i, stmt := range statements {
+		for _, rule := range l.rules {
+			severity := l.Severity(rule)
+			if severity == SeverityOff {
+				continue
+			}
+
+			rule.Check(stmt, stmt.Node(), func(node ast.Node, message string) {
+				finding := Finding{
+					Rule:           rule.Name(),
+					Severity:       severity,
+					Message:        message,
+					Line:           stmt.StartLine,
+					Column:         stmt.StartColumn,
+					StartOffset:    stmt.StartOffset,
+					EndOffset:      stmt.EndOffset,
+					StatementIndex: i,
+				}
+				if node != nil {
+					span := node.Span()
+					finding.Line, finding.Column = span.Start.Line, span.Start.Column
+					finding.StartOffset, finding.EndOffset = span.Start.Offset, span.End.Offset
+				}
+				if !suppressions.suppressed(finding) {
+					findings = append(findings, finding)
+				}
+			})
+		}
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return findings[i].StartOffset < findings[j].StartOffset
+	})
+	return findings, nil
+}
+
+// checkConfig reports the rules named by the configuration that the Linter
+// does not have
+func (l *Linter) checkConfig() error {
+	if l.config == nil {
+		return nil
+	}
+	for name := range l.config.Rules {
+		if l.rule(name) == nil {
+			return fmt.Errorf("%w: %s", ErrUnknownRule, name)
+		}
+	}
+	return nil
+}
+
+// rule returns the rule with the given name, or nil
+func (l *Linter) rule(name string) Rule {
+	for _, rule := range l.rules {
+		if rule.Name() == name {
+			return rule
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package lint
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/splitter"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/statement"
+)
+
+// truncateRule is a custom rule that reports every TRUNCATE statement
+type truncateRule struct{}
+
+func (truncateRule) Name() string              { return "no-truncate" }
+func (truncateRule) Description() string       { return "TRUNCATE cannot be rolled back" }
+func (truncateRule) DefaultSeverity() Severity { return SeverityWarning }
+
+func (truncateRule) Check(stmt splitter.Statement, tree ast.Node, report ReportFunc) {
+	if stmt.Type == statement.TypeTruncate {
+		report(nil, "TRUNCATE cannot be rolled back")
+	}
+}
+
+func TestLinter_LintString(t *testing.T) {
+	input := "SELECT * FROM dual;\nDELETE FROM orders;\nUPDATE orders SET status = 'X';\n"
+
+	findings, err := NewLinter().LintString(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(findings) != 2 {
+		t.Fatalf("Expected 2 findings, got %+v", findings)
+	}
+
+	got := findings[0]
+	if got.Rule != RuleDeleteWithoutWhere || got.Severity != SeverityError || got.StatementIndex != 1 {
+		t.Errorf("Expected %s in statement 1, got %+v", RuleDeleteWithoutWhere, got)
+	}
+	if got.Line != 2 || got.Column != 0 || input[got.StartOffset:got.EndOffset] != "DELETE FROM orders" {
+		t.Errorf("Expected the finding to cover the DELETE, got %+v", got)
+	}
+	if s := got.String(); s != "2:0: error: DELETE without a WHERE clause affects every row of the table (delete-without-where)" {
+		t.Errorf("Unexpected string %q", s)
+	}
+	if findings[1].Rule != RuleUpdateWithoutWhere {
+		t.Errorf("Expected %s second, got %+v", RuleUpdateWithoutWhere, findings[1])
+	}
+}
+
+func TestLinter_WithConfig(t *testing.T) {
+	config, err := ParseConfig([]byte(`{"rules": {"delete-without-where": "off", "update-without-where": "info"}}`))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	findings, err := NewLinter(WithConfig(config)).LintString("DELETE FROM orders;\nUPDATE orders SET status = 'X';\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != RuleUpdateWithoutWhere || findings[0].Severity != SeverityInfo {
+		t.Errorf("Expected only an info finding for the UPDATE, got %+v", findings)
+	}
+
+	unknown := &Config{Rules: map[string]Severity{"no-such-rule": SeverityError}}
+	if _, err := NewLinter(WithConfig(unknown)).LintString("SELECT 1 FROM dual;"); !errors.Is(err, ErrUnknownRule) {
+		t.Errorf("Expected ErrUnknownRule, got %v", err)
+	}
+}
+
+func TestLinter_WithRules(t *testing.T) {
+	linter := NewLinter(WithRules(truncateRule{}))
+	if rules := linter.Rules(); rules[len(rules)-1].Name() != "no-truncate" {
+		t.Fatalf("Expected the custom rule after the built-in ones")
+	}
+
+	findings, err := linter.LintString("SELECT 1 FROM dual;\nTRUNCATE TABLE tmp_orders;\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].Rule != "no-truncate" || findings[0].Line != 2 || findings[0].StatementIndex != 1 {
+		t.Errorf("Expected the custom rule at the start of statement 1, got %+v", findings)
+	}
+}
+
+func TestLinter_SyntaxError(t *testing.T) {
+	_, err := NewLinter().LintString("DELETE FROM;\n")
+	if !errors.Is(err, splitter.ErrSyntax) {
+		t.Errorf("Expected a syntax error, got %v", err)
+	}
+}
+
+func TestLinter_LintFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cleanup.sql")
+	if err := os.WriteFile(path, []byte("DROP TABLE tmp_orders;\n"), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+
+	findings, err := NewLinter().LintFile(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(findings) != 1 || findings[0].File != path || !strings.HasPrefix(findings[0].String(), path+":1:0: info: ") {
+		t.Errorf("Expected an info finding in %s, got %+v", path, findings)
+	}
+
+	if _, err := NewLinter().LintFile(filepath.Join(t.TempDir(), "missing.sql")); !errors.Is(err, splitter.ErrReadFile) {
+		t.Errorf("Expected ErrReadFile, got %v", err)
+	}
+}
@@ -0,0 +1,242 @@
+package lint
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/splitter"
+)
+
+// Names of the built-in rules
+const (
+	RuleDeleteWithoutWhere    = "delete-without-where"
+	RuleUpdateWithoutWhere    = "update-without-where"
+	RuleSelectStarInView      = "select-star-in-view"
+	RuleGrantToPublic         = "grant-to-public"
+	RuleWhenOthersNull        = "when-others-null"
+	RuleDropTableWithoutPurge = "drop-table-without-purge"
+	RuleCommitInTrigger       = "commit-in-trigger"
+	RuleMissingOrReplace      = "missing-or-replace"
+)
+
+// codeObjects are the rules of the CREATE statements of stored code
+var codeObjects = map[string]string{
+	"create_procedure_body": "procedure",
+	"create_function_body":  "function",
+	"create_package":        "package",
+	"create_package_body":   "package body",
+	"create_trigger":        "trigger",
+	"create_type":           "type",
+}
+
+// BuiltinRules returns the rules that every Linter starts with
+func BuiltinRules() []Rule {
+	return []Rule{
+		&rule{
+			name:        RuleDeleteWithoutWhere,
+			description: "DELETE statements without a WHERE clause remove every row of the table",
+			severity:    SeverityError,
+			check:       withoutWhere("delete_statement", "DELETE"),
+		},
+		&rule{
+			name:        RuleUpdateWithoutWhere,
+			description: "UPDATE statements without a WHERE clause change every row of the table",
+			severity:    SeverityError,
+			check:       withoutWhere("update_statement", "UPDATE"),
+		},
+		&rule{
+			name:        RuleSelectStarInView,
+			description: "Views selecting * change their columns when the underlying tables change",
+			severity:    SeverityWarning,
+			check:       checkSelectStarInView,
+		},
+		&rule{
+			name:        RuleGrantToPublic,
+			description: "Privileges granted to PUBLIC are granted to every user of the database",
+			severity:    SeverityError,
+			check:       checkGrantToPublic,
+		},
+		&rule{
+			name:        RuleWhenOthersNull,
+			description: "WHEN OTHERS THEN NULL handlers hide every error",
+			severity:    SeverityError,
+			check:       checkWhenOthersNull,
+		},
+		&rule{
+			name:        RuleDropTableWithoutPurge,
+			description: "DROP TABLE without PURGE keeps the table in the recycle bin, using its space",
+			severity:    SeverityInfo,
+			check:       checkDropTableWithoutPurge,
+		},
+		&rule{
+			name:        RuleCommitInTrigger,
+			description: "COMMIT in a trigger fails at run time unless the trigger is an autonomous transaction",
+			severity:    SeverityError,
+			check:       checkCommitInTrigger,
+		},
+		&rule{
+			name:        RuleMissingOrReplace,
+			description: "CREATE of stored code without OR REPLACE fails when the script is run again",
+			severity:    SeverityWarning,
+			check:       checkMissingOrReplace,
+		},
+	}
+}
+
+// rule is a built-in Rule
+type rule struct {
+	name        string
+	description string
+	severity    Severity
+	check       func(tree ast.Node, report ReportFunc)
+}
+
+func (r *rule) Name() string              { return r.name }
+func (r *rule) Description() string       { return r.description }
+func (r *rule) DefaultSeverity() Severity { return r.severity }
+
+func (r *rule) Check(_ splitter.Statement, tree ast.Node, report ReportFunc) {
+	r.check(tree, report)
+}
+
+// withoutWhere returns a check for the DML statements of a grammar rule that
+// have no WHERE clause, including those inside PL/SQL
+func withoutWhere(ruleName, keyword string) func(ast.Node, ReportFunc) {
+	return func(tree ast.Node, report ReportFunc) {
+		for _, dml := range ast.Find(tree, ruleName) {
+			if childRule(dml, "where_clause") == nil {
+				report(dml, keyword+" without a WHERE clause affects every row of the table")
+			}
+		}
+	}
+}
+
+func checkSelectStarInView(tree ast.Node, report ReportFunc) {
+	for _, view := range ast.Find(tree, "create_view") {
+		for _, list := range ast.Find(view, "selected_list") {
+			// The * of scalar and EXISTS subqueries does not reach the view
+			if insideRule(list, "expression", view) {
+				continue
+			}
+			if first := list.Child(0); first != nil && first.Text() == "*" {
+				report(first, "view selects *, list its columns instead")
+				continue
+			}
+			for _, wild := range ast.Find(list, "table_wild") {
+				report(wild, fmt.Sprintf("view selects %s, list its columns instead", wild.Text()))
+			}
+		}
+	}
+}
+
+func checkGrantToPublic(tree ast.Node, report ReportFunc) {
+	for _, grant := range ast.Find(tree, "grant_statement") {
+		if public := childToken(grant, "PUBLIC"); public != nil {
+			report(public, "privileges are granted to PUBLIC, grant them to a role instead")
+		}
+	}
+}
+
+func checkWhenOthersNull(tree ast.Node, report ReportFunc) {
+	for _, handler := range ast.Find(tree, "exception_handler") {
+		others := false
+		for _, name := range childRules(handler, "exception_name") {
+			others = others || strings.EqualFold(name.Text(), "OTHERS")
+		}
+		if others && onlyNull(childRule(handler, "seq_of_statements")) {
+			report(handler, "WHEN OTHERS THEN NULL hides every error, log or re-raise it")
+		}
+	}
+}
+
+// onlyNull reports whether a sequence of statements holds only NULL statements
+func onlyNull(statements ast.Node) bool {
+	if statements == nil {
+		return false
+	}
+	for _, stmt := range childRules(statements, "statement") {
+		if stmt.ChildCount() != 1 || stmt.Child(0).RuleName() != "null_statement" {
+			return false
+		}
+	}
+	return true
+}
+
+func checkDropTableWithoutPurge(tree ast.Node, report ReportFunc) {
+	for _, drop := range ast.Find(tree, "drop_table") {
+		if childToken(drop, "PURGE") == nil {
+			report(drop, "DROP TABLE without PURGE keeps the table in the recycle bin")
+		}
+	}
+}
+
+func checkCommitInTrigger(tree ast.Node, report ReportFunc) {
+	for _, trigger := range ast.Find(tree, "create_trigger") {
+		autonomous := false
+		for _, pragma := range ast.Find(trigger, "pragma_declaration") {
+			autonomous = autonomous || childToken(pragma, "AUTONOMOUS_TRANSACTION") != nil
+		}
+		if autonomous {
+			continue
+		}
+		for _, commit := range ast.Find(trigger, "commit_statement") {
+			report(commit, "COMMIT in a trigger raises ORA-04092 unless the trigger is PRAGMA AUTONOMOUS_TRANSACTION")
+		}
+	}
+}
+
+func checkMissingOrReplace(tree ast.Node, report ReportFunc) {
+	ast.Walk(tree, func(node ast.Node) bool {
+		object, ok := codeObjects[node.RuleName()]
+		if !ok {
+			return true
+		}
+		if childRule(node, "type_body") != nil {
+			object = "type body"
+		}
+		if childToken(node, "REPLACE") == nil {
+			report(node, fmt.Sprintf("CREATE %s without OR REPLACE fails if the %s exists", strings.ToUpper(object), object))
+		}
+		return false
+	})
+}
+
+// childRule returns the first direct child of n with the given rule name, or nil
+func childRule(n ast.Node, ruleName string) ast.Node {
+	if rules := childRules(n, ruleName); len(rules) > 0 {
+		return rules[0]
+	}
+	return nil
+}
+
+// childRules returns the direct children of n with the given rule name
+func childRules(n ast.Node, ruleName string) []ast.Node {
+	var found []ast.Node
+	for _, child := range n.Children() {
+		if child.Kind() == ast.KindRule && child.RuleName() == ruleName {
+			found = append(found, child)
+		}
+	}
+	return found
+}
+
+// childToken returns the first direct child of n with the given token name, or nil
+func childToken(n ast.Node, tokenName string) ast.Node {
+	for _, child := range n.Children() {
+		if child.Kind() == ast.KindToken && child.TokenName() == tokenName {
+			return child
+		}
+	}
+	return nil
+}
+
+// insideRule reports whether a rule with the given name encloses n below root
+func insideRule(n ast.Node, ruleName string, root ast.Node) bool {
+	for parent := n.Parent(); parent != nil && parent.Span() != root.Span(); parent = parent.Parent() {
+		if parent.RuleName() == ruleName {
+			return true
+		}
+	}
+	return false
+}
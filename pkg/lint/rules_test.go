@@ -0,0 +1,128 @@
+package lint
+
+import (
+	"testing"
+)
+
+func TestBuiltinRules(t *testing.T) {
+	tests := []struct {
+		name   string
+		rule   string
+		input  string
+		lines  []int // Lines of the expected findings
+		column int   // Column of the first finding
+	}{
+		{
+			name:  "delete without where",
+			rule:  RuleDeleteWithoutWhere,
+			input: "DELETE FROM orders WHERE id = 1;\nDELETE FROM order_lines;\n",
+			lines: []int{2},
+		},
+		{
+			name:   "delete in a block",
+			rule:   RuleDeleteWithoutWhere,
+			input:  "BEGIN\n  DELETE FROM orders;\nEND;\n/\n",
+			lines:  []int{2},
+			column: 2,
+		},
+		{
+			name:  "update without where",
+			rule:  RuleUpdateWithoutWhere,
+			input: "UPDATE orders SET status = 'X';\nUPDATE orders SET status = 'Y' WHERE id = 1;\n",
+			lines: []int{1},
+		},
+		{
+			name:   "select star in view",
+			rule:   RuleSelectStarInView,
+			input:  "CREATE OR REPLACE VIEW v AS SELECT * FROM orders;\n",
+			lines:  []int{1},
+			column: 35,
+		},
+		{
+			name:   "table star in view",
+			rule:   RuleSelectStarInView,
+			input:  "CREATE OR REPLACE VIEW v AS\nSELECT o.*, c.name FROM orders o JOIN customers c ON c.id = o.customer_id;\n",
+			lines:  []int{2},
+			column: 7,
+		},
+		{
+			name:  "select star in an exists subquery",
+			rule:  RuleSelectStarInView,
+			input: "CREATE OR REPLACE VIEW v AS\nSELECT id FROM orders o WHERE EXISTS (SELECT * FROM order_lines l WHERE l.order_id = o.id);\n",
+		},
+		{
+			name:   "grant to public",
+			rule:   RuleGrantToPublic,
+			input:  "GRANT SELECT ON orders TO reporting;\nGRANT SELECT ON orders TO PUBLIC;\n",
+			lines:  []int{2},
+			column: 26,
+		},
+		{
+			name:   "when others then null",
+			rule:   RuleWhenOthersNull,
+			input:  "BEGIN\n  run;\nEXCEPTION\n  WHEN no_data_found THEN NULL;\n  WHEN OTHERS THEN NULL;\nEND;\n/\n",
+			lines:  []int{5},
+			column: 2,
+		},
+		{
+			name:  "when others that logs",
+			rule:  RuleWhenOthersNull,
+			input: "BEGIN\n  run;\nEXCEPTION\n  WHEN OTHERS THEN\n    log_error;\n    RAISE;\nEND;\n/\n",
+		},
+		{
+			name:  "drop table without purge",
+			rule:  RuleDropTableWithoutPurge,
+			input: "DROP TABLE tmp_orders;\nDROP TABLE tmp_lines PURGE;\n",
+			lines: []int{1},
+		},
+		{
+			name:   "commit in trigger",
+			rule:   RuleCommitInTrigger,
+			input:  "CREATE OR REPLACE TRIGGER orders_audit\nAFTER INSERT ON orders\nFOR EACH ROW\nBEGIN\n  INSERT INTO audit_log VALUES (:new.id);\n  COMMIT;\nEND;\n/\n",
+			lines:  []int{6},
+			column: 2,
+		},
+		{
+			name:  "commit in autonomous trigger",
+			rule:  RuleCommitInTrigger,
+			input: "CREATE OR REPLACE TRIGGER orders_audit\nAFTER INSERT ON orders\nFOR EACH ROW\nDECLARE\n  PRAGMA AUTONOMOUS_TRANSACTION;\nBEGIN\n  INSERT INTO audit_log VALUES (:new.id);\n  COMMIT;\nEND;\n/\n",
+		},
+		{
+			name:  "missing or replace",
+			rule:  RuleMissingOrReplace,
+			input: "CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\n/\nCREATE OR REPLACE PACKAGE pkg IS\n  PROCEDURE p;\nEND;\n/\nCREATE TABLE t (id NUMBER);\n",
+			lines: []int{1},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			findings, err := NewLinter().LintString(tc.input)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var lines []int
+			var first *Finding
+			for i, f := range findings {
+				if f.Rule == tc.rule {
+					lines = append(lines, f.Line)
+					if first == nil {
+						first = &findings[i]
+					}
+				}
+			}
+			if len(lines) != len(tc.lines) {
+				t.Fatalf("Expected %s findings on lines %v, got %+v", tc.rule, tc.lines, findings)
+			}
+			for i := range lines {
+				if lines[i] != tc.lines[i] {
+					t.Errorf("Expected %s findings on lines %v, got %v", tc.rule, tc.lines, lines)
+				}
+			}
+			if first != nil && first.Column != tc.column {
+				t.Errorf("Expected the first finding at column %d, got %d", tc.column, first.Column)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package lint
+
+import (
+	"strings"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/lexer"
+)
+
+// directivePrefix starts the comments that suppress findings
+const directivePrefix = "lint:"
+
+// suppressions are the suppression comments of a script
+type suppressions struct {
+	lines   []lineSuppression
+	regions []regionDirective // In script order
+}
+
+// lineSuppression suppresses findings on one line
+type lineSuppression struct {
+	line  int
+	rules map[string]bool // nil for every rule
+}
+
+// regionDirective is a lint:disable or lint:enable comment, which applies
+// from its offset to the next directive that undoes it
+type regionDirective struct {
+	offset  int
+	disable bool
+	rules   map[string]bool // nil for every rule
+}
+
+// parseSuppressions finds the suppression comments of a script
+func parseSuppressions(content string) *suppressions {
+	s := &suppressions{}
+	for _, tok := range lexer.Tokenize(content) {
+		if tok.Kind != lexer.KindComment {
+			continue
+		}
+
+		text := strings.TrimSpace(tok.Text)
+		switch {
+		case strings.HasPrefix(text, "--"):
+			text = text[2:]
+		case strings.HasPrefix(text, "/*"):
+			text = strings.TrimSuffix(text[2:], "*/")
+		default:
+			continue // REMARK comments are SQL*Plus commands
+		}
+
+		fields := strings.Fields(strings.ReplaceAll(text, ",", " "))
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], directivePrefix) {
+			continue
+		}
+		var rules map[string]bool
+		if len(fields) > 1 {
+			rules = make(map[string]bool, len(fields)-1)
+			for _, name := range fields[1:] {
+				rules[name] = true
+			}
+		}
+
+		switch strings.TrimPrefix(fields[0], directivePrefix) {
+		case "disable-line":
+			s.lines = append(s.lines, lineSuppression{line: tok.Span.Start.Line, rules: rules})
+		case "disable-next-line":
+			// Single-line comments end with their newline
+			next := tok.Span.End.Line
+			if !strings.HasSuffix(tok.Text, "\n") {
+				next++
+			}
+			s.lines = append(s.lines, lineSuppression{line: next, rules: rules})
+		case "disable":
+			s.regions = append(s.regions, regionDirective{offset: tok.Span.Start.Offset, disable: true, rules: rules})
+		case "enable":
+			s.regions = append(s.regions, regionDirective{offset: tok.Span.Start.Offset, rules: rules})
+		}
+	}
+	return s
+}
+
+// suppressed reports whether a finding is suppressed by a comment
+func (s *suppressions) suppressed(f Finding) bool {
+	for _, line := range s.lines {
+		if line.line == f.Line && (line.rules == nil || line.rules[f.Rule]) {
+			return true
+		}
+	}
+
+	// The last directive before the finding that names its rule decides
+	disabled := false
+	for _, region := range s.regions {
+		if region.offset > f.StartOffset {
+			break
+		}
+		if region.rules == nil || region.rules[f.Rule] {
+			disabled = region.disable
+		}
+	}
+	return disabled
+}
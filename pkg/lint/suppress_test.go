@@ -0,0 +1,59 @@
+package lint
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSuppressions(t *testing.T) {
+	input := `-- lint:disable-next-line delete-without-where
+DELETE FROM staging_orders;
+DELETE FROM staging_lines; -- lint:disable-line
+DROP TABLE tmp_orders; /* lint:disable-line update-without-where */
+/* lint:disable delete-without-where, update-without-where */
+DELETE FROM staging_customers;
+UPDATE orders SET status = 'X';
+-- lint:enable update-without-where
+UPDATE orders SET status = 'Y';
+DELETE FROM staging_addresses;
+-- lint:enable
+DELETE FROM staging_payments;
+`
+
+	findings, err := NewLinter().LintString(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var got []string
+	for _, f := range findings {
+		got = append(got, fmt.Sprintf("%s@%d", f.Rule, f.Line))
+	}
+	want := []string{
+		RuleDropTableWithoutPurge + "@4",
+		RuleUpdateWithoutWhere + "@9",
+		RuleDeleteWithoutWhere + "@12",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Expected %v, got %v", want, got)
+		}
+	}
+}
+
+func TestParseSuppressions(t *testing.T) {
+	s := parseSuppressions("SELECT 1 FROM dual; -- lint:disable-line a, b\n/* lint:disable-next-line\n */\nSELECT 2 FROM dual;\n-- not a lint:disable comment\n")
+
+	if len(s.regions) != 0 || len(s.lines) != 2 {
+		t.Fatalf("Expected 2 line suppressions, got %+v", s)
+	}
+	if s.lines[0].line != 1 || !s.lines[0].rules["a"] || !s.lines[0].rules["b"] || len(s.lines[0].rules) != 2 {
+		t.Errorf("Expected rules a and b on line 1, got %+v", s.lines[0])
+	}
+	if s.lines[1].line != 4 || s.lines[1].rules != nil {
+		t.Errorf("Expected every rule on line 4, got %+v", s.lines[1])
+	}
+}
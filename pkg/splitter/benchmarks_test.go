@@ -0,0 +1,64 @@
+package splitter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/test/samples"
+)
+
+// benchmarkScript is a generated deployment script of about 2 MB
+var benchmarkScript = []byte(samples.GenerateLargeScript(2 << 20))
+
+// BenchmarkSplitBytes splits a script in place, without copying it
+func BenchmarkSplitBytes(b *testing.B) {
+	s := NewSplitter()
+	b.SetBytes(int64(len(benchmarkScript)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SplitBytes(benchmarkScript); err != nil {
+			b.Fatalf("SplitBytes failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkSplitString_FromBytes converts the script to a string first, as
+// callers holding a byte slice had to before SplitBytes
+func BenchmarkSplitString_FromBytes(b *testing.B) {
+	s := NewSplitter()
+	b.SetBytes(int64(len(benchmarkScript)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SplitString(string(benchmarkScript)); err != nil {
+			b.Fatalf("SplitString failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkSplitReader reads the script before splitting it in place
+func BenchmarkSplitReader(b *testing.B) {
+	s := NewSplitter()
+	b.SetBytes(int64(len(benchmarkScript)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SplitReader(bytes.NewReader(benchmarkScript)); err != nil {
+			b.Fatalf("SplitReader failed: %v", err)
+		}
+	}
+}
+
+// BenchmarkSplitBytes_Lexical splits in place using the token stream only
+func BenchmarkSplitBytes_Lexical(b *testing.B) {
+	s := NewSplitter(WithMode(ModeLexical), WithContent(false))
+	b.SetBytes(int64(len(benchmarkScript)))
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := s.SplitBytes(benchmarkScript); err != nil {
+			b.Fatalf("SplitBytes failed: %v", err)
+		}
+	}
+}
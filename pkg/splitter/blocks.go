@@ -0,0 +1,30 @@
+package splitter
+
+import (
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// Codes of the errors found by the block structure checks enabled with
+// WithBlockChecks. Such errors have Kind SemanticError.
+const (
+	CodeEndLabelMismatch    = internalParser.CodeEndLabelMismatch    // The name after END is not the name of the unit or the label of the block
+	CodeLoopLabelMismatch   = internalParser.CodeLoopLabelMismatch   // The label after END LOOP is not the label of the loop
+	CodeRaiseOutsideHandler = internalParser.CodeRaiseOutsideHandler // RAISE without an exception is outside an exception handler
+	CodeMissingReturn       = internalParser.CodeMissingReturn       // A function can reach its END without a RETURN
+	CodeUnreachableReturn   = internalParser.CodeUnreachableReturn   // A RETURN follows a statement that never completes
+)
+
+// WithBlockChecks configures whether block structure is checked beyond what
+// the grammar accepts. The Oracle compiler rejects an END name that is not
+// the name of its procedure, function or package, an END or END LOOP label
+// that is not the label of its block or loop, and a RAISE without an
+// exception outside an exception handler. A function that can reach its END
+// without a RETURN fails at run time, and a RETURN after a statement that
+// never completes cannot run. These are reported as syntax errors of Kind
+// SemanticError. Checks run in the same pass as the parse, in ModeParser and
+// ModeHybrid.
+func WithBlockChecks(check bool) Option {
+	return func(s *Splitter) {
+		s.blockChecks = check
+	}
+}
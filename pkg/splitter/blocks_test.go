@@ -0,0 +1,29 @@
+package splitter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitter_WithBlockChecks(t *testing.T) {
+	input := "CREATE OR REPLACE PROCEDURE foo IS\nBEGIN\n  NULL;\nEND wrong_name;\n/\n"
+
+	if _, err := NewSplitter().SplitString(input); err != nil {
+		t.Fatalf("Expected the grammar to accept the script, got %v", err)
+	}
+
+	_, err := NewSplitter(WithBlockChecks(true)).SplitString(input)
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) || !errors.Is(err, ErrSyntax) {
+		t.Fatalf("Expected a SyntaxError, got %v", err)
+	}
+	if syntaxErr.Kind != SemanticError || syntaxErr.Code != CodeEndLabelMismatch {
+		t.Errorf("Expected a semantic %s error, got %s %s: %s", CodeEndLabelMismatch, syntaxErr.Kind, syntaxErr.Code, syntaxErr.Message)
+	}
+	if syntaxErr.Line != 4 || syntaxErr.Column != 4 || syntaxErr.StatementIndex != 0 {
+		t.Errorf("Expected the error at 4:4 in statement 0, got %d:%d in %d", syntaxErr.Line, syntaxErr.Column, syntaxErr.StatementIndex)
+	}
+	if syntaxErr.Token == nil || syntaxErr.Token.Text != "wrong_name" {
+		t.Errorf("Expected the END name as the token, got %+v", syntaxErr.Token)
+	}
+}
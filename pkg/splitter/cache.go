@@ -0,0 +1,138 @@
+package splitter
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sync"
+
+	"github.com/zodimo/go-plsql-statement-splitter/internal/parser/gen"
+)
+
+// cacheFormat is bumped whenever a change to the splitter alters its results
+// for the same input, so that entries written by older code are ignored
+const cacheFormat = 7
+
+const modulePath = "github.com/zodimo/go-plsql-statement-splitter"
+
+// WithCache configures a directory in which split results are cached. Entries
+// are keyed on a hash of the script, the splitter version, the grammar and
+// the options that affect the result, so scripts that have not changed since
+// an earlier run are not parsed again. The directory is created if needed and
+// can be shared by concurrent processes.
+//
+// Results are not cached when the Splitter keeps parse trees or has
+// listeners, as both need a real parse.
+func WithCache(dir string) Option {
+	return func(s *Splitter) {
+		if dir != "" {
+			s.cache = &resultCache{dir: dir}
+		}
+	}
+}
+
+// resultCache stores split results as JSON files under dir
+type resultCache struct {
+	dir string
+}
+
+// cacheEntry is the serialized result of splitting a script
+type cacheEntry struct {
+	Statements []Statement  `json:"statements"`
+	Error      *SyntaxError `json:"error,omitempty"`
+	Errors     SyntaxErrors `json:"errors,omitempty"` // With WithVerboseErrors(true)
+}
+
+// cacheVersion identifies the code that produced a cache entry
+var cacheVersion = sync.OnceValue(func() string {
+	version := "devel"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if info.Main.Path == modulePath {
+			version = info.Main.Version
+		}
+		for _, dep := range info.Deps {
+			if dep.Path == modulePath {
+				version = dep.Version
+			}
+		}
+	}
+	return fmt.Sprintf("%d/%s/%s", cacheFormat, version, gen.GrammarChecksum())
+})
+
+// cacheKey returns the cache key of splitting content with the options of s
+func (s *Splitter) cacheKey(content string) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%t %t %d %t %t %d %d %t %s %t %v %d %d %t %t\n",
+		cacheVersion(),
+		s.includePosition,
+		s.verboseErrors,
+		s.maxErrors,
+		s.includeContext,
+		s.includeErrorStatement,
+		s.contextLines,
+		s.mode,
+		s.omitContent,
+		s.oracleVersion,
+		s.ccFlags != nil,
+		s.ccFlags,
+		s.terminators.SQL,
+		s.terminators.Block,
+		s.terminators.BlankLinesEnd,
+		s.blockChecks,
+	)
+	h.Write([]byte(content))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// path returns the file holding the entry for key
+func (c *resultCache) path(key string) string {
+	return filepath.Join(c.dir, key[:2], key+".json")
+}
+
+// load returns the entry for key. Missing, unreadable and corrupt entries are
+// all reported as a miss.
+func (c *resultCache) load(key string) (cacheEntry, bool) {
+	var entry cacheEntry
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// store writes the entry for key. The entry is written to a temporary file and
+// renamed into place, so concurrent readers never see a partial entry.
+// Failures are ignored as the cache is only an optimization.
+func (c *resultCache) store(key string, entry cacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+
+	path := c.path(key)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+}
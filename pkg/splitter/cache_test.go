@@ -0,0 +1,117 @@
+package splitter
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// cacheFiles returns the cache entries written under dir
+func cacheFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	files, err := filepath.Glob(filepath.Join(dir, "*", "*.json"))
+	if err != nil {
+		t.Fatalf("Error listing cache: %v", err)
+	}
+	return files
+}
+
+func TestSplitter_WithCache(t *testing.T) {
+	dir := t.TempDir()
+	input := "SELECT * FROM employees;\nBEGIN NULL; END;\n/"
+
+	first, err := NewSplitter(WithCache(dir)).SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	files := cacheFiles(t, dir)
+	if len(files) != 1 {
+		t.Fatalf("Expected 1 cache entry, got %d", len(files))
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatalf("Error reading cache entry: %v", err)
+	}
+	second, err := NewSplitter(WithCache(dir)).SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("Cached result differs\nparsed: %+v\ncached: %+v", first, second)
+	}
+
+	// Replace the entry so that a hit is distinguishable from a parse
+	if err := os.WriteFile(files[0], []byte(`{"statements":[{"content":"from cache"}]}`), 0644); err != nil {
+		t.Fatalf("Error writing cache entry: %v", err)
+	}
+	cached, err := NewSplitter(WithCache(dir)).SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if len(cached) != 1 || cached[0].Content != "from cache" {
+		t.Errorf("Expected the cached entry to be used, got %+v", cached)
+	}
+
+	// A corrupt entry is a miss and gets rewritten
+	if err := os.WriteFile(files[0], []byte("{"), 0644); err != nil {
+		t.Fatalf("Error writing cache entry: %v", err)
+	}
+	reparsed, err := NewSplitter(WithCache(dir)).SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if !reflect.DeepEqual(first, reparsed) {
+		t.Errorf("Corrupt entry should be ignored, got %+v", reparsed)
+	}
+	if rewritten, _ := os.ReadFile(files[0]); string(rewritten) != string(data) {
+		t.Errorf("Corrupt entry should be replaced")
+	}
+}
+
+func TestSplitter_WithCache_SyntaxError(t *testing.T) {
+	dir := t.TempDir()
+	input := "SELECT * FROM WHERE;"
+
+	_, parseErr := NewSplitter(WithCache(dir)).SplitString(input)
+	if parseErr == nil {
+		t.Fatal("Expected a syntax error")
+	}
+
+	_, cachedErr := NewSplitter(WithCache(dir)).SplitString(input)
+	syntaxErr, ok := cachedErr.(*SyntaxError)
+	if !ok {
+		t.Fatalf("Expected a cached *SyntaxError, got %T", cachedErr)
+	}
+	if syntaxErr.Error() != parseErr.Error() {
+		t.Errorf("Cached error differs\nparsed: %v\ncached: %v", parseErr, syntaxErr)
+	}
+}
+
+func TestSplitter_WithCache_KeyedOnOptions(t *testing.T) {
+	dir := t.TempDir()
+	input := "SELECT * FROM employees;"
+
+	for _, s := range []*Splitter{
+		NewSplitter(WithCache(dir)),
+		NewSplitter(WithCache(dir), WithPositionInfo(false)),
+		NewSplitter(WithCache(dir), WithMode(ModeLexical)),
+		NewSplitter(WithCache(dir), WithContent(false)),
+	} {
+		if _, err := s.SplitString(input); err != nil {
+			t.Fatalf("SplitString failed: %v", err)
+		}
+	}
+	if files := cacheFiles(t, dir); len(files) != 4 {
+		t.Errorf("Expected an entry per option set, got %d", len(files))
+	}
+
+	// Parse trees are never cached
+	if _, err := NewSplitter(WithCache(dir), WithParseTree(true)).SplitString("COMMIT;"); err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if files := cacheFiles(t, dir); len(files) != 4 {
+		t.Errorf("Expected no entry for a splitter keeping parse trees, got %d entries", len(files))
+	}
+}
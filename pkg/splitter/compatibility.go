@@ -0,0 +1,57 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// CodeUnsupportedSyntax is the code of the warnings of CompatibilityReport
+// about syntax that the target release does not support
+const CodeUnsupportedSyntax = internalParser.CodeUnsupportedSyntax
+
+// CompatibilityReport returns the syntax of a script that target does not
+// support, in script order, such as identity columns, FETCH FIRST, LISTAGG
+// ... ON OVERFLOW, JSON functions, APPROX_COUNT_DISTINCT or PL/SQL in WITH
+// clauses. Each warning has MinVersion set to the first release supporting
+// the syntax. Syntax added in 12.2 requires 18c, the first later release
+// that can be targeted.
+//
+// The script is parsed in ModeParser with the syntax of every release, so
+// the release configured with WithOracleVersion does not matter. Conditional
+// compilation is evaluated for target, with the flags of WithCCFlags, so
+// code selected by DBMS_DB_VERSION for later releases is not reported. A
+// script with syntax errors is rejected as by SplitString.
+func (s *Splitter) CompatibilityReport(content string, target OracleVersion) ([]Diagnostic, error) {
+	if strings.TrimSpace(content) == "" {
+		return []Diagnostic{}, nil
+	}
+
+	// Without flags the first branch of every $IF would be parsed
+	ccFlags := s.ccFlags
+	if ccFlags == nil {
+		ccFlags = map[string]any{}
+	}
+
+	checker := internalParser.NewCompatibilityChecker(content, target.internal())
+	_, syntaxErrors, err := internalParser.Parse(content, internalParser.ParseOptions{
+		MaxErrors:    s.maxErrors,
+		ContextLines: s.contextLines,
+		Listeners:    []antlr.ParseTreeListener{checker},
+		Version:      target.internal(),
+		AllVersions:  true,
+		CCFlags:      ccFlags,
+		Terminators:  s.terminators,
+		BlockChecks:  s.blockChecks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsing, err)
+	}
+	if len(syntaxErrors) > 0 {
+		return nil, s.rejection(syntaxErrors)
+	}
+
+	return s.diagnostics(checker.Warnings()), nil
+}
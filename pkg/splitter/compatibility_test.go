@@ -0,0 +1,66 @@
+package splitter
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSplitter_CompatibilityReport(t *testing.T) {
+	input := "CREATE TABLE orders (id NUMBER GENERATED ALWAYS AS IDENTITY, doc CLOB);\n" +
+		"SELECT JSON_VALUE(doc, '$.status') FROM orders FETCH FIRST 10 ROWS ONLY;\n"
+
+	// The configured version does not reject the syntax being reported
+	s := NewSplitter(WithOracleVersion(Oracle11g))
+	diagnostics, err := s.CompatibilityReport(input, Oracle11g)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		message      string
+		line, column int
+		statement    int
+	}{
+		{"identity column requires Oracle 12c or later, but the target is 11g", 1, 31, 0},
+		{"JSON_VALUE requires Oracle 12c or later, but the target is 11g", 2, 7, 1},
+		{"FETCH FIRST requires Oracle 12c or later, but the target is 11g", 2, 47, 1},
+	}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(want), diagnostics)
+	}
+	for i, w := range want {
+		got := diagnostics[i]
+		if got.Code != CodeUnsupportedSyntax || got.Message != w.message || got.MinVersion != Oracle12c {
+			t.Errorf("Expected %q requiring 12c, got %s %q requiring %s", w.message, got.Code, got.Message, got.MinVersion)
+		}
+		if got.Line != w.line || got.Column != w.column || got.StatementIndex != w.statement {
+			t.Errorf("Expected %d:%d in statement %d, got %d:%d in %d", w.line, w.column, w.statement, got.Line, got.Column, got.StatementIndex)
+		}
+	}
+
+	if diagnostics, err := s.CompatibilityReport(input, Oracle12c); err != nil || len(diagnostics) != 0 {
+		t.Errorf("Expected no diagnostics for 12c, got %+v, %v", diagnostics, err)
+	}
+}
+
+func TestDiagnostic_MinVersionJSON(t *testing.T) {
+	data, err := json.Marshal(Diagnostic{Code: CodeUnsupportedSyntax, MinVersion: Oracle18c})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), `"minVersion":"18c"`) {
+		t.Errorf("Expected the release name in %s", data)
+	}
+
+	var decoded Diagnostic
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded.MinVersion != Oracle18c {
+		t.Errorf("Expected 18c back, got %s, %v", decoded.MinVersion, err)
+	}
+
+	// Other warnings have no minimum version
+	data, _ = json.Marshal(Diagnostic{Code: CodeUnitWithoutSlash})
+	if strings.Contains(string(data), "minVersion") {
+		t.Errorf("Expected no minVersion in %s", data)
+	}
+}
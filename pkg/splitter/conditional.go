@@ -0,0 +1,121 @@
+package splitter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// Branch is one branch of a conditional compilation $IF directive
+type Branch struct {
+	Directive   string `json:"directive"`           // $IF, $ELSIF or $ELSE
+	Condition   string `json:"condition,omitempty"` // Text of the condition, empty for $ELSE
+	Line        int    `json:"line"`                // Line of the directive
+	Column      int    `json:"column"`              // Column of the directive
+	StartOffset int    `json:"startOffset"`         // Byte offset of the branch's code, just after $THEN or $ELSE
+	EndOffset   int    `json:"endOffset"`           // Byte offset just past the branch's code
+	Depth       int    `json:"depth"`               // Number of enclosing $IF directives
+	Live        bool   `json:"live"`                // Whether the branch is compiled
+}
+
+// WithCCFlags evaluates PL/SQL conditional compilation directives with the
+// given values, like the PLSQL_CCFLAGS parameter does. Keys are inquiry
+// directive names such as "debug" for $$debug, or package constants such as
+// "my_pkg.trace", and values are bool, integers, strings or nil. Names that
+// are not given are NULL, except the DBMS_DB_VERSION constants, which follow
+// WithOracleVersion, and a few predefined directives such as $$PLSQL_LINE.
+//
+// Only the live branches are parsed, so their syntax is checked as it would
+// be compiled, and live $ERROR directives are reported as syntax errors.
+// Without WithCCFlags, directives are accepted but not evaluated, and the
+// first branch of every $IF is parsed.
+func WithCCFlags(flags map[string]any) Option {
+	return func(s *Splitter) {
+		s.ccFlags = make(map[string]any, len(flags))
+		for name, value := range flags {
+			s.ccFlags[strings.ToLower(name)] = ccValue(value)
+		}
+	}
+}
+
+// ccValue converts integer flag values to int, the only integer type that
+// directive conditions compare
+func ccValue(value any) any {
+	switch v := value.(type) {
+	case int8:
+		return int(v)
+	case int16:
+		return int(v)
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case uint8:
+		return int(v)
+	case uint16:
+		return int(v)
+	case uint32:
+		return int(v)
+	}
+	return value
+}
+
+// ParseCCFlags parses flags written like the PLSQL_CCFLAGS parameter, such as
+// "debug:TRUE, level:2". Values are TRUE, FALSE, NULL or integers.
+func ParseCCFlags(flags string) (map[string]any, error) {
+	values := make(map[string]any)
+	if strings.TrimSpace(flags) == "" {
+		return values, nil
+	}
+
+	for _, flag := range strings.Split(flags, ",") {
+		name, value, ok := strings.Cut(flag, ":")
+		name, value = strings.TrimSpace(name), strings.TrimSpace(value)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid conditional compilation flag %q, expected name:value", strings.TrimSpace(flag))
+		}
+
+		switch strings.ToUpper(value) {
+		case "TRUE":
+			values[name] = true
+		case "FALSE":
+			values[name] = false
+		case "NULL":
+			values[name] = nil
+		default:
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value %q for conditional compilation flag %q, expected TRUE, FALSE, NULL or an integer", value, name)
+			}
+			values[name] = n
+		}
+	}
+	return values, nil
+}
+
+// Branches returns the $IF, $ELSIF and $ELSE branches of a script and whether
+// each one is live under the WithCCFlags and WithOracleVersion settings. The
+// script is only lexed, not parsed. Errors in the directives, including live
+// $ERROR directives, are returned as a *SyntaxError together with the
+// branches.
+func (s *Splitter) Branches(content string) ([]Branch, error) {
+	found, syntaxErrors := internalParser.Branches(content, internalParser.ParseOptions{
+		MaxErrors:    s.maxErrors,
+		ContextLines: s.contextLines,
+		Version:      s.oracleVersion.internal(),
+		CCFlags:      s.ccFlags,
+	})
+
+	branches := make([]Branch, len(found))
+	for i, b := range found {
+		branches[i] = Branch(b)
+	}
+
+	if len(syntaxErrors) > 0 {
+		syntaxErr := s.syntaxError(syntaxErrors[0])
+		return branches, &syntaxErr
+	}
+	return branches, nil
+}
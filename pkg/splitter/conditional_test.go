@@ -0,0 +1,106 @@
+package splitter
+
+import (
+	"strings"
+	"testing"
+)
+
+const conditionalPackage = `CREATE OR REPLACE PACKAGE BODY logger AS
+  PROCEDURE log(msg VARCHAR2) IS
+  BEGIN
+    $IF $$debug $THEN
+      DBMS_OUTPUT.PUT_LINE(msg);
+    $ELSIF DBMS_DB_VERSION.VER_LE_12_1 $THEN
+      NULL;
+    $ELSE
+      $ERROR 'logging needs debug or 12.1' $END
+    $END
+  END log;
+END logger;
+/
+SELECT 1 FROM dual;`
+
+func TestParseCCFlags(t *testing.T) {
+	flags, err := ParseCCFlags("debug:TRUE, level : 2,trace:null, quiet:FALSE")
+	if err != nil {
+		t.Fatalf("ParseCCFlags failed: %v", err)
+	}
+	expected := map[string]any{"debug": true, "level": 2, "trace": nil, "quiet": false}
+	if len(flags) != len(expected) {
+		t.Fatalf("Expected %d flags, got %v", len(expected), flags)
+	}
+	for name, value := range expected {
+		if got, ok := flags[name]; !ok || got != value {
+			t.Errorf("Flag %s: expected %v, got %v", name, value, got)
+		}
+	}
+
+	for _, invalid := range []string{"debug", "debug:yes", ":TRUE"} {
+		if _, err := ParseCCFlags(invalid); err == nil {
+			t.Errorf("Expected an error for %q", invalid)
+		}
+	}
+}
+
+func TestSplitter_ConditionalCompilation(t *testing.T) {
+	// Without flags the first branch of every $IF is parsed
+	statements, err := NewSplitter().SplitString(conditionalPackage)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Expected the package body and the query, got %d statements", len(statements))
+	}
+	if !strings.Contains(statements[0].Content, "$ELSE") {
+		t.Errorf("Expected the directives to stay in the package body, got %q", statements[0].Content)
+	}
+
+	for _, flags := range []map[string]any{{"DEBUG": true}, {"debug": int64(1)}} {
+		if _, err := NewSplitter(WithCCFlags(flags)).SplitString(conditionalPackage); err != nil {
+			t.Errorf("Expected flags %v to select a valid branch, got %v", flags, err)
+		}
+	}
+
+	// 11g selects the $ELSIF branch
+	if _, err := NewSplitter(WithCCFlags(nil), WithOracleVersion(Oracle11g)).SplitString(conditionalPackage); err != nil {
+		t.Errorf("Expected the $ELSIF branch on 11g, got %v", err)
+	}
+
+	// Otherwise the live $ERROR is reported
+	_, err = NewSplitter(WithCCFlags(map[string]any{"debug": false})).SplitString(conditionalPackage)
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("Expected a syntax error, got %v", err)
+	}
+	if !strings.Contains(syntaxErr.Message, "logging needs debug or 12.1") || syntaxErr.Line != 9 {
+		t.Errorf("Expected the $ERROR message on line 9, got %q on line %d", syntaxErr.Message, syntaxErr.Line)
+	}
+}
+
+func TestSplitter_Branches(t *testing.T) {
+	branches, err := NewSplitter(WithCCFlags(map[string]any{"debug": true})).Branches(conditionalPackage)
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+
+	expected := []Branch{
+		{Directive: "$IF", Condition: "$$debug", Line: 4, Depth: 0, Live: true},
+		{Directive: "$ELSIF", Condition: "DBMS_DB_VERSION.VER_LE_12_1", Line: 6, Depth: 0, Live: false},
+		{Directive: "$ELSE", Line: 8, Depth: 0, Live: false},
+	}
+	if len(branches) != len(expected) {
+		t.Fatalf("Expected %d branches, got %+v", len(expected), branches)
+	}
+	for i, want := range expected {
+		got := branches[i]
+		if got.Directive != want.Directive || got.Condition != want.Condition || got.Line != want.Line ||
+			got.Depth != want.Depth || got.Live != want.Live {
+			t.Errorf("Branch %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	live := branches[0]
+	if code := conditionalPackage[live.StartOffset:live.EndOffset]; strings.TrimSpace(code) != "DBMS_OUTPUT.PUT_LINE(msg);" {
+		t.Errorf("Expected the live branch's code, got %q", code)
+	}
+}
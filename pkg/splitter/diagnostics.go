@@ -0,0 +1,42 @@
+package splitter
+
+import (
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// ErrorKind tells whether a syntax error was found by the lexer, the parser or
+// the block structure checks
+type ErrorKind string
+
+const (
+	// ParserError is an error in the order of the tokens, such as a missing
+	// keyword, or an error in a directive or version check
+	ParserError ErrorKind = ErrorKind(internalParser.ParserError)
+
+	// LexicalError is an error in the characters, such as an invalid character
+	// or an unterminated string. The lexer skips what it cannot read, so the
+	// parser errors that follow it are often caused by it.
+	LexicalError ErrorKind = ErrorKind(internalParser.LexicalError)
+
+	// SemanticError is code that follows the grammar but that the compiler
+	// rejects or that fails at run time, such as an END name that does not
+	// match the unit. It is only reported with WithBlockChecks(true).
+	SemanticError ErrorKind = ErrorKind(internalParser.SemanticError)
+)
+
+// Codes of the syntax errors whose likely cause is recognized. Such errors
+// explain the cause in their Message, point at it rather than at where the
+// parser gave up, and keep the parser's message in ParserMessage. Lexical
+// errors always have a code.
+const (
+	CodeMissingEnd             = internalParser.CodeMissingEnd             // A block is not closed by an END
+	CodeUnmatchedEnd           = internalParser.CodeUnmatchedEnd           // An END closes no block
+	CodeMissingSlash           = internalParser.CodeMissingSlash           // A PL/SQL unit is not followed by a / line
+	CodeUnterminatedString     = internalParser.CodeUnterminatedString     // A string literal is not closed
+	CodeUnterminatedComment    = internalParser.CodeUnterminatedComment    // A /* comment is not closed
+	CodeMissingComma           = internalParser.CodeMissingComma           // Two list items are not separated by a comma
+	CodeReservedWord           = internalParser.CodeReservedWord           // A reserved word is used as an identifier
+	CodeMissingSemicolon       = internalParser.CodeMissingSemicolon       // A statement is not terminated
+	CodeInvalidCharacter       = internalParser.CodeInvalidCharacter       // A character cannot start any token
+	CodeUnterminatedIdentifier = internalParser.CodeUnterminatedIdentifier // A quoted identifier is not closed
+)
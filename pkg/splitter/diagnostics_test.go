@@ -0,0 +1,66 @@
+package splitter
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSplitter_ErrorCodes(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		kind   ErrorKind
+		code   string
+		line   int
+		column int
+	}{
+		{"missing END", "SELECT * FROM dual;\nBEGIN\n  UPDATE t SET x = 1;\n", ParserError, CodeMissingEnd, 2, 0},
+		{"unterminated string", "SELECT * FROM dual;\nSELECT 'abc FROM dual;\n", LexicalError, CodeUnterminatedString, 2, 7},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewSplitter().SplitString(tc.input)
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("Expected SyntaxError, got %v", err)
+			}
+			if syntaxErr.Kind != tc.kind || syntaxErr.Code != tc.code {
+				t.Fatalf("Expected %s error %s, got %s %q: %s", tc.kind, tc.code, syntaxErr.Kind, syntaxErr.Code, syntaxErr.Message)
+			}
+			if syntaxErr.Line != tc.line || syntaxErr.Column != tc.column {
+				t.Errorf("Expected the cause at %d:%d, got %d:%d", tc.line, tc.column, syntaxErr.Line, syntaxErr.Column)
+			}
+			if tc.kind == ParserError && syntaxErr.ParserMessage == "" {
+				t.Error("Expected the parser's message to be kept")
+			}
+			if !strings.Contains(err.Error(), tc.code+": ") {
+				t.Errorf("Expected the code in %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestSplitter_LexicalErrors(t *testing.T) {
+	// The lexer skips the backquote, after which the statement parses
+	syntaxErrors, err := NewSplitter().GetSyntaxErrors("SELECT 1 ` FROM dual;\n")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(syntaxErrors) != 1 {
+		t.Fatalf("Expected 1 error, got %+v", syntaxErrors)
+	}
+
+	got := syntaxErrors[0]
+	if got.Kind != LexicalError || got.Code != CodeInvalidCharacter || got.Line != 1 || got.Column != 9 {
+		t.Errorf("Expected an invalid character at 1:9, got %s %s at %d:%d", got.Kind, got.Code, got.Line, got.Column)
+	}
+	if got.Token == nil || got.Token.Text != "`" || got.Token.StartOffset != 9 || got.Token.EndOffset != 10 {
+		t.Errorf("Expected the backquote at [9, 10), got %+v", got.Token)
+	}
+
+	if _, err := NewSplitter().SplitString("SELECT 1 ` FROM dual;\n"); !errors.Is(err, ErrSyntax) {
+		t.Errorf("Expected the lexical error from SplitString, got %v", err)
+	}
+}
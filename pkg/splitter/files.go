@@ -0,0 +1,215 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+	"sync"
+	"unsafe"
+)
+
+// ErrSkipped is reported for files that were not split because an earlier
+// file failed and the Splitter was configured with WithStopOnError
+var ErrSkipped = errors.New("skipped after an earlier failure")
+
+// FileResult is the outcome of splitting a single file
+type FileResult struct {
+	Path       string       `json:"path"`
+	Statements []Statement  `json:"statements"`
+	Warnings   []Diagnostic `json:"warnings,omitempty"`
+	Err        error        `json:"-"` // Read, syntax or cancellation error for this file
+}
+
+// WithConcurrency configures the number of files split in parallel by
+// SplitFiles and SplitFS. It defaults to GOMAXPROCS.
+func WithConcurrency(workers int) Option {
+	return func(s *Splitter) {
+		if workers > 0 {
+			s.concurrency = workers
+		}
+	}
+}
+
+// WithStopOnError configures whether SplitFiles and SplitFS stop scheduling
+// new files after the first file fails
+func WithStopOnError(stop bool) Option {
+	return func(s *Splitter) {
+		s.stopOnError = stop
+	}
+}
+
+// SplitFiles splits the given files using a bounded pool of workers. The
+// results are in the same order as paths, whatever order the files finish in.
+//
+// The returned error is nil when every file was split. Otherwise it is the
+// context error if ctx was cancelled, or else the error of the first failed
+// file in path order. Per-file errors are always available in the results.
+func (s *Splitter) SplitFiles(ctx context.Context, paths []string) ([]FileResult, error) {
+	return s.splitAll(ctx, paths, os.ReadFile)
+}
+
+// SplitFS splits every file in fsys whose name matches pattern, such as
+// "*.sql" or "*.{sql,pks,pkb,trg}". Patterns without a slash are matched
+// against the base name of files at any depth, patterns with a slash against
+// the full path. Files are processed as in SplitFiles and the results are in
+// lexical path order. fsys can be an embed.FS.
+func (s *Splitter) SplitFS(ctx context.Context, fsys fs.FS, pattern string) ([]FileResult, error) {
+	patterns := expandBraces(pattern)
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+
+	var paths []string
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		subject := path.Base(name)
+		if strings.Contains(pattern, "/") {
+			subject = name
+		}
+		for _, p := range patterns {
+			if matched, _ := path.Match(p, subject); matched {
+				paths = append(paths, name)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing files: %w", err)
+	}
+
+	return s.splitAll(ctx, paths, func(name string) ([]byte, error) {
+		return fs.ReadFile(fsys, name)
+	})
+}
+
+// splitAll reads and splits each path with a bounded pool of workers
+func (s *Splitter) splitAll(ctx context.Context, paths []string, readFile func(string) ([]byte, error)) ([]FileResult, error) {
+	results := make([]FileResult, len(paths))
+	for i, p := range paths {
+		results[i] = FileResult{Path: p}
+	}
+
+	// failed is closed by the first failure when stopping on errors
+	failed := make(chan struct{})
+	var failOnce sync.Once
+
+	workers := s.concurrency
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(paths) {
+		workers = len(paths)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				// Files handed out just before a failure was noticed are skipped too
+				select {
+				case <-failed:
+					results[i].Err = ErrSkipped
+					continue
+				default:
+				}
+
+				results[i].Statements, results[i].Warnings, results[i].Err = s.splitFile(ctx, results[i].Path, readFile)
+				if results[i].Err != nil && s.stopOnError {
+					failOnce.Do(func() { close(failed) })
+				}
+			}
+		}()
+	}
+
+	// Schedule files in order until done, cancelled or stopped by a failure
+	next := 0
+schedule:
+	for ; next < len(paths); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break schedule
+		case <-failed:
+			break schedule
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	for i := next; i < len(paths); i++ {
+		if ctx.Err() != nil {
+			results[i].Err = ctx.Err()
+		} else {
+			results[i].Err = ErrSkipped
+		}
+	}
+
+	if err := ctx.Err(); err != nil {
+		return results, err
+	}
+	for _, result := range results {
+		if result.Err != nil && result.Err != ErrSkipped {
+			return results, fmt.Errorf("%s: %w", result.Path, result.Err)
+		}
+	}
+	return results, nil
+}
+
+// splitFile reads and splits a single file unless ctx is already done, and
+// returns its warnings
+func (s *Splitter) splitFile(ctx context.Context, name string, readFile func(string) ([]byte, error)) ([]Statement, []Diagnostic, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	data, err := readFile(name)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrReadFile, err)
+	}
+
+	content := unsafe.String(unsafe.SliceData(data), len(data))
+	statements, err := s.splitString(content)
+	warnings := s.Warnings(content)
+	for i := range warnings {
+		warnings[i].File = name
+	}
+
+	if err == nil {
+		if promoted := firstPromoted(warnings); promoted != nil {
+			return nil, warnings, promoted
+		}
+	}
+	return statements, warnings, withFile(err, name)
+}
+
+// expandBraces expands the non-nested {a,b,c} groups of a pattern
+func expandBraces(pattern string) []string {
+	open := strings.IndexByte(pattern, '{')
+	closing := strings.IndexByte(pattern, '}')
+	if open < 0 || closing < open {
+		return []string{pattern}
+	}
+
+	var patterns []string
+	for _, alternative := range strings.Split(pattern[open+1:closing], ",") {
+		patterns = append(patterns, expandBraces(pattern[:open]+alternative+pattern[closing+1:])...)
+	}
+	return patterns
+}
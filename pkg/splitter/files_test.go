@@ -0,0 +1,149 @@
+package splitter
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+//go:embed testdata/scripts
+var embeddedScripts embed.FS
+
+func TestSplitter_SplitFiles(t *testing.T) {
+	dir := t.TempDir()
+	paths := []string{
+		filepath.Join(dir, "a.sql"),
+		filepath.Join(dir, "missing.sql"),
+		filepath.Join(dir, "b.sql"),
+		filepath.Join(dir, "c.sql"),
+	}
+	contents := map[string]string{
+		paths[0]: "SELECT * FROM employees;",
+		paths[2]: "DELETE FROM employees; COMMIT;",
+		paths[3]: "SELECT * FROM;",
+	}
+	for p, content := range contents {
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", p, err)
+		}
+	}
+
+	results, err := NewSplitter(WithConcurrency(2)).SplitFiles(context.Background(), paths)
+	if err == nil {
+		t.Fatalf("Expected an error for the missing file")
+	}
+	if len(results) != len(paths) {
+		t.Fatalf("Expected %d results, got %d", len(paths), len(results))
+	}
+
+	// Results follow the order of the paths
+	for i, result := range results {
+		if result.Path != paths[i] {
+			t.Errorf("Result %d: expected path %s, got %s", i, paths[i], result.Path)
+		}
+	}
+	if results[0].Err != nil || len(results[0].Statements) != 1 {
+		t.Errorf("Expected a.sql to split into 1 statement, got %+v", results[0])
+	}
+	if !errors.Is(results[1].Err, os.ErrNotExist) || !errors.Is(results[1].Err, ErrReadFile) {
+		t.Errorf("Expected a not-exist error for missing.sql, got %v", results[1].Err)
+	}
+	if results[2].Err != nil || len(results[2].Statements) != 2 {
+		t.Errorf("Expected b.sql to split into 2 statements, got %+v", results[2])
+	}
+	if _, ok := results[3].Err.(*SyntaxError); !ok {
+		t.Errorf("Expected a syntax error for c.sql, got %v", results[3].Err)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the first failure in path order, got %v", err)
+	}
+}
+
+func TestSplitter_SplitFiles_StopOnError(t *testing.T) {
+	fsys := fstest.MapFS{
+		"01_bad.sql": {Data: []byte("SELECT * FROM;")},
+	}
+	for _, name := range []string{"02.sql", "03.sql", "04.sql", "05.sql"} {
+		fsys[name] = &fstest.MapFile{Data: []byte("COMMIT;")}
+	}
+
+	results, err := NewSplitter(WithConcurrency(1), WithStopOnError(true)).SplitFS(context.Background(), fsys, "*.sql")
+	if err == nil {
+		t.Fatalf("Expected an error")
+	}
+	if len(results) != 5 {
+		t.Fatalf("Expected 5 results, got %d", len(results))
+	}
+
+	skipped := 0
+	for _, result := range results {
+		if errors.Is(result.Err, ErrSkipped) {
+			skipped++
+		}
+	}
+	if skipped == 0 {
+		t.Errorf("Expected files after the failure to be skipped")
+	}
+}
+
+func TestSplitter_SplitFiles_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results, err := NewSplitter().SplitFiles(ctx, []string{"a.sql", "b.sql"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected context.Canceled, got %v", err)
+	}
+	for _, result := range results {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("Expected %s to be cancelled, got %v", result.Path, result.Err)
+		}
+	}
+}
+
+func TestSplitter_SplitFS_Embed(t *testing.T) {
+	results, err := NewSplitter().SplitFS(context.Background(), embeddedScripts, "*.{sql,pks,pkb,trg}")
+	if err != nil {
+		t.Fatalf("SplitFS failed: %v", err)
+	}
+
+	var paths []string
+	counts := make(map[string]int)
+	for _, result := range results {
+		paths = append(paths, result.Path)
+		counts[result.Path] = len(result.Statements)
+	}
+
+	expected := []string{
+		"testdata/scripts/packages/payroll.pkb",
+		"testdata/scripts/packages/payroll.pks",
+		"testdata/scripts/tables.sql",
+	}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Fatalf("Expected paths %v, got %v", expected, paths)
+	}
+	if counts["testdata/scripts/tables.sql"] != 2 {
+		t.Errorf("Expected 2 statements in tables.sql, got %d", counts["testdata/scripts/tables.sql"])
+	}
+}
+
+func TestSplitter_SplitFS_InvalidPattern(t *testing.T) {
+	if _, err := NewSplitter().SplitFS(context.Background(), fstest.MapFS{}, "[.sql"); err == nil {
+		t.Errorf("Expected an error for a malformed pattern")
+	}
+}
+
+func TestExpandBraces(t *testing.T) {
+	got := expandBraces("*.{sql,pks}")
+	if !reflect.DeepEqual(got, []string{"*.sql", "*.pks"}) {
+		t.Errorf("Unexpected expansion %v", got)
+	}
+	if got := expandBraces("*.sql"); !reflect.DeepEqual(got, []string{"*.sql"}) {
+		t.Errorf("Pattern without braces should be unchanged, got %v", got)
+	}
+}
@@ -0,0 +1,58 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/antlr4-go/antlr/v4"
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// Codes of the warnings about identifiers that parse but that the release
+// configured with WithOracleVersion rejects, or that name another object than
+// they appear to
+const (
+	CodeReservedIdentifier = internalParser.CodeReservedIdentifier // An unquoted name is a reserved word
+	CodeIdentifierTooLong  = internalParser.CodeIdentifierTooLong  // An identifier is longer than the release allows
+	CodeQuotedCaseVariant  = internalParser.CodeQuotedCaseVariant  // A quoted identifier differs from another only by case
+)
+
+// CheckIdentifiers returns the warnings about the identifiers of a script in
+// script order:
+//
+//   - unquoted names of objects, columns, variables and aliases that are
+//     reserved words, such as a column named LEVEL
+//   - identifiers longer than the release configured with WithOracleVersion
+//     allows: 30 bytes up to 12c, which is 12.1, and 128 bytes from 18c on
+//   - quoted identifiers that differ from another identifier of the script
+//     only by case, such as "Emp" and EMP
+//
+// Reserved words used as pseudocolumns or functions, such as SYSDATE and
+// LEVEL in an expression, are not reported. The script is parsed in
+// ModeParser whatever the configured mode, and a script with syntax errors
+// is rejected as by SplitString. WithWarningsAsErrors sets the severity of
+// these warnings too.
+func (s *Splitter) CheckIdentifiers(content string) ([]Diagnostic, error) {
+	if strings.TrimSpace(content) == "" {
+		return []Diagnostic{}, nil
+	}
+
+	checker := internalParser.NewIdentifierChecker(content, s.oracleVersion.internal())
+	_, syntaxErrors, err := internalParser.Parse(content, internalParser.ParseOptions{
+		MaxErrors:    s.maxErrors,
+		ContextLines: s.contextLines,
+		Listeners:    []antlr.ParseTreeListener{checker},
+		Version:      s.oracleVersion.internal(),
+		CCFlags:      s.ccFlags,
+		Terminators:  s.terminators,
+		BlockChecks:  s.blockChecks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsing, err)
+	}
+	if len(syntaxErrors) > 0 {
+		return nil, s.rejection(syntaxErrors)
+	}
+
+	return s.diagnostics(checker.Warnings()), nil
+}
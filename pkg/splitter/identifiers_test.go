@@ -0,0 +1,52 @@
+package splitter
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestSplitter_CheckIdentifiers(t *testing.T) {
+	input := "CREATE TABLE customer_order_line_item_details (level NUMBER);\nSELECT * FROM \"Customer_Order_Line_Item_Details\";\n"
+
+	diagnostics, err := NewSplitter(WithOracleVersion(Oracle12c)).CheckIdentifiers(input)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	want := []struct {
+		code         string
+		line, column int
+		statement    int
+	}{
+		{CodeIdentifierTooLong, 1, 13, 0},
+		{CodeReservedIdentifier, 1, 47, 0},
+		{CodeIdentifierTooLong, 2, 14, 1},
+		{CodeQuotedCaseVariant, 2, 14, 1},
+	}
+	if len(diagnostics) != len(want) {
+		t.Fatalf("Expected %d diagnostics, got %+v", len(want), diagnostics)
+	}
+	for i, w := range want {
+		got := diagnostics[i]
+		if got.Code != w.code || got.Line != w.line || got.Column != w.column || got.StatementIndex != w.statement {
+			t.Errorf("Expected %s at %d:%d in statement %d, got %+v", w.code, w.line, w.column, w.statement, got)
+		}
+		if got.Severity != SeverityWarning {
+			t.Errorf("Expected a warning, got %s", got.Severity)
+		}
+	}
+
+	// Later releases allow 128 bytes
+	diagnostics, err = NewSplitter(WithOracleVersion(Oracle19c)).CheckIdentifiers(input)
+	if err != nil || len(diagnostics) != 2 {
+		t.Errorf("Expected 2 diagnostics for 19c, got %+v, %v", diagnostics, err)
+	}
+}
+
+func TestSplitter_CheckIdentifiers_SyntaxError(t *testing.T) {
+	_, err := NewSplitter().CheckIdentifiers("SELECT * FROM;\n")
+	var syntaxErr *SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("Expected a SyntaxError, got %v", err)
+	}
+}
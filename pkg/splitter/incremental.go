@@ -0,0 +1,231 @@
+package splitter
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/lexer"
+)
+
+// Result is a split script that can be kept up to date with Splitter.Update as
+// the script is edited, for example in an editor
+type Result struct {
+	Text       string       // The script the statements were split from
+	Statements []*Statement // Statements in script order
+	Warnings   []Diagnostic // Warnings about the whole script, see Splitter.Warnings
+
+	clean bool // The whole text lexes cleanly, so regions can be split on their own
+}
+
+// Edit replaces the bytes [Start, End) of a script with Text
+type Edit struct {
+	Start int    `json:"start"` // Byte offset of the first replaced byte
+	End   int    `json:"end"`   // Byte offset just past the last replaced byte
+	Text  string `json:"text"`  // Replacement text, empty for a deletion
+}
+
+// SplitResult splits a script into a Result that can be updated incrementally.
+// Statements in a Result always carry position information, whatever the
+// WithPositionInfo setting. Results are neither cached nor given parse trees,
+// and listeners are not called.
+func (s *Splitter) SplitResult(content string) (*Result, error) {
+	return s.Update(&Result{}, Edit{Text: content})
+}
+
+// Update applies an edit to the script of prev and re-splits only the
+// statements the edit touches. Statements before the edit are kept as they are
+// and statements after it are moved by the size of the edit, so both keep
+// their identity; the statements of prev are updated in place and prev must
+// not be used afterwards.
+//
+// The re-split region starts at the statement containing the edit and grows
+// until the statements after the edit are found at their old boundaries. With
+// ModeParser a syntax error re-splits the rest of the script, and Update
+// returns the error together with a Result that has the edited text and no
+// statements; the next Update then splits the whole script. Editors usually
+// want ModeHybrid, which never fails.
+func (s *Splitter) Update(prev *Result, edit Edit) (*Result, error) {
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len(prev.Text) {
+		return nil, fmt.Errorf("invalid edit range [%d, %d) for a script of %d bytes", edit.Start, edit.End, len(prev.Text))
+	}
+
+	text := prev.Text[:edit.Start] + edit.Text + prev.Text[edit.End:]
+	delta := len(edit.Text) - (edit.End - edit.Start)
+	old := prev.Statements
+
+	// An edit can merge its statement with the next one or split it, so the
+	// region starts at the last statement that begins before the edit
+	lo := sort.Search(len(old), func(i int) bool { return old[i].StartOffset >= edit.Start }) - 1
+	if lo < 0 {
+		lo = 0
+	}
+	hi := sort.Search(len(old), func(i int) bool { return old[i].StartOffset >= edit.End })
+
+	// An unterminated string or comment anywhere can be closed by the edit, so
+	// scripts that do not lex cleanly are split again as a whole. So are
+	// scripts that change the SQL*Plus terminators, as their statements depend
+	// on every SET command before them.
+	whole := !prev.clean || internalParser.SetsTerminators(text)
+	if whole {
+		lo, hi = 0, len(old)
+	}
+
+	// Tokens such as a slash line depend on the rest of their line, so the
+	// region starts on a line where the first statement is the first token
+	start, line := 0, 1
+	if !whole && lo < len(old) && old[lo].StartOffset < edit.Start {
+		for ; lo > 0; lo-- {
+			if _, ok := lineStart(prev.Text, old[lo].StartOffset); ok {
+				break
+			}
+		}
+		if offset, ok := lineStart(prev.Text, old[lo].StartOffset); ok {
+			start, line = offset, old[lo].StartLine
+		}
+	}
+
+	// Statements found in a region are relative to it, so incremental updates
+	// need positions and cannot use trees, listeners or the cache
+	inc := *s
+	inc.includePosition = true
+	inc.includeParseTree = false
+	inc.listeners = nil
+	inc.cache = nil
+
+	// The region ends after the first statement that follows the edit. When
+	// the region's last statement is that statement, unchanged, the text after
+	// it is split as before; otherwise the edit spilled over and the region grows.
+	var statements []Statement
+	clean := true
+	for {
+		end := len(text)
+		if hi+1 < len(old) {
+			end = old[hi+1].StartOffset + delta
+		}
+
+		region := text[start:end]
+		var err error
+		statements, err = inc.splitRegion(region)
+		if end == len(text) {
+			if err != nil {
+				return &Result{Text: text}, err
+			}
+			hi = len(old)
+			clean = lexesCleanly(region)
+			break
+		}
+		if n := len(statements); err == nil && n > 0 && lexesCleanly(region) &&
+			statements[n-1].StartOffset+start == old[hi].StartOffset+delta &&
+			statements[n-1].EndOffset+start == old[hi].EndOffset+delta {
+			hi++
+			break
+		}
+
+		// Grow geometrically so that an unterminated block costs O(n) overall
+		hi += hi - lo + 1
+	}
+
+	result := &Result{Text: text, Statements: make([]*Statement, 0, len(old)-(hi-lo)+len(statements)), clean: clean}
+	result.Statements = append(result.Statements, old[:lo]...)
+
+	// Statements of the region that the edit did not touch keep their identity
+	type span struct {
+		stmt *Statement
+		end  int
+	}
+	untouched := make(map[int]span)
+	for _, stmt := range old[lo:hi] {
+		if stmt.EndOffset <= edit.Start {
+			untouched[stmt.StartOffset] = span{stmt, stmt.EndOffset}
+		} else if stmt.StartOffset >= edit.End {
+			untouched[stmt.StartOffset+delta] = span{stmt, stmt.EndOffset + delta}
+		}
+	}
+	for _, stmt := range statements {
+		stmt.StartOffset += start
+		stmt.EndOffset += start
+		stmt.StartLine += line - 1
+		stmt.EndLine += line - 1
+
+		if same, ok := untouched[stmt.StartOffset]; ok && same.end == stmt.EndOffset {
+			*same.stmt = stmt
+			result.Statements = append(result.Statements, same.stmt)
+			continue
+		}
+		stmt := stmt
+		result.Statements = append(result.Statements, &stmt)
+	}
+
+	// Move the statements after the region; only those on the line where the
+	// edit ends also move sideways
+	lineDelta := strings.Count(edit.Text, "\n") - strings.Count(prev.Text[edit.Start:edit.End], "\n")
+	columnDelta := columnAt(text, edit.Start+len(edit.Text)) - columnAt(prev.Text, edit.End)
+	sameLine := true
+	for _, stmt := range old[hi:] {
+		sameLine = sameLine && !strings.Contains(prev.Text[edit.End:stmt.StartOffset], "\n")
+		if sameLine {
+			stmt.StartColumn += columnDelta
+			if stmt.StartLine == stmt.EndLine {
+				stmt.EndColumn += columnDelta
+			}
+		}
+		stmt.StartOffset += delta
+		stmt.EndOffset += delta
+		stmt.StartLine += lineDelta
+		stmt.EndLine += lineDelta
+		result.Statements = append(result.Statements, stmt)
+	}
+
+	// Warnings depend on the SET commands before them, so they are found again
+	// for the whole script
+	result.Warnings = s.Warnings(text)
+	return result, nil
+}
+
+// splitRegion splits part of a script, which may be only whitespace
+func (s *Splitter) splitRegion(region string) ([]Statement, error) {
+	if strings.TrimSpace(region) == "" {
+		return nil, nil
+	}
+	return s.split(region)
+}
+
+// lexesCleanly reports whether every character of region belongs to a token
+// and no string or comment is left open, so that the tokens of the region do
+// not depend on the text that follows it
+func lexesCleanly(region string) bool {
+	offset := 0
+	var previous lexer.Token
+	for tok := range lexer.New(region).All() {
+		if tok.Span.Start.Offset != offset {
+			return false // The lexer skipped characters it could not match
+		}
+		switch {
+		case tok.Name == "SQ":
+			return false // An unterminated string
+		case previous.Name == "SOLIDUS" && strings.HasPrefix(tok.Text, "*"):
+			return false // An unterminated comment
+		case strings.HasPrefix(tok.Text, "'") && previous.Kind == lexer.KindIdentifier &&
+			strings.TrimPrefix(strings.ToLower(previous.Text), "n") == "q":
+			return false // An unterminated q'[...]' string
+		}
+		offset = tok.Span.End.Offset
+		previous = tok
+	}
+	return offset == len(region)
+}
+
+// lineStart returns the start of the line holding offset and whether only
+// whitespace precedes offset on that line
+func lineStart(text string, offset int) (int, bool) {
+	start := strings.LastIndexByte(text[:offset], '\n') + 1
+	return start, strings.TrimSpace(text[start:offset]) == ""
+}
+
+// columnAt returns the 0-based character column of a byte offset
+func columnAt(text string, offset int) int {
+	return utf8.RuneCountInString(text[strings.LastIndexByte(text[:offset], '\n')+1 : offset])
+}
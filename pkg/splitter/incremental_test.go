@@ -0,0 +1,152 @@
+package splitter
+
+import (
+	"strings"
+	"testing"
+)
+
+const incrementalScript = `-- Employees
+CREATE TABLE employees (emp_id NUMBER, emp_name VARCHAR2(100));
+INSERT INTO employees VALUES (1, 'Zoë');
+
+CREATE OR REPLACE PROCEDURE touch_all AS
+BEGIN
+  UPDATE employees SET emp_name = emp_name;
+END touch_all;
+/
+SELECT * FROM employees; SELECT COUNT(*) FROM employees;
+BEGIN
+  touch_all;
+END;
+/
+COMMIT;
+`
+
+// values returns copies of the statements of a Result
+func values(result *Result) []Statement {
+	statements := make([]Statement, len(result.Statements))
+	for i, stmt := range result.Statements {
+		statements[i] = *stmt
+	}
+	return statements
+}
+
+// edit builds the edit that replaces the first occurrence of old in text
+func edit(t *testing.T, text, old, new string) Edit {
+	t.Helper()
+	start := strings.Index(text, old)
+	if start < 0 {
+		t.Fatalf("%q not found in script", old)
+	}
+	return Edit{Start: start, End: start + len(old), Text: new}
+}
+
+func TestSplitter_Update_MatchesFullSplit(t *testing.T) {
+	edits := []struct {
+		name     string
+		old, new string
+	}{
+		{"rename column", "emp_name VARCHAR2(100)", "full_name VARCHAR2(200)"},
+		{"edit inside block", "UPDATE employees SET", "UPDATE  employees\n  SET"},
+		{"insert statement", "COMMIT;", "DELETE FROM employees;\nCOMMIT;"},
+		{"remove terminator", "SELECT * FROM employees;", "SELECT * FROM employees"},
+		{"split statement", "VALUES (1, 'Zoë');", "VALUES (1, 'Zoë'); SELECT 1 FROM dual;"},
+		{"same line", "SELECT * FROM", "SELECT emp_id FROM"},
+		{"open block", "-- Employees", "BEGIN"},
+		{"open comment", "SELECT * FROM", "/* SELECT * FROM"},
+		{"open string", "'Zoë'", "'Zoë"},
+		{"delete everything before commit", incrementalScript[:strings.Index(incrementalScript, "COMMIT")], ""},
+	}
+
+	modes := map[string]Mode{"parser": ModeParser, "hybrid": ModeHybrid, "lexical": ModeLexical}
+	for modeName, mode := range modes {
+		s := NewSplitter(WithMode(mode))
+		for _, e := range edits {
+			t.Run(modeName+"/"+e.name, func(t *testing.T) {
+				prev, err := s.SplitResult(incrementalScript)
+				if err != nil {
+					t.Fatalf("SplitResult failed: %v", err)
+				}
+
+				ed := edit(t, prev.Text, e.old, e.new)
+				updated, updateErr := s.Update(prev, ed)
+				full, fullErr := s.SplitResult(updated.Text)
+
+				if (updateErr != nil) != (fullErr != nil) {
+					t.Fatalf("Update error %v, full split error %v", updateErr, fullErr)
+				}
+				got, want := values(updated), values(full)
+				if len(got) != len(want) {
+					t.Fatalf("Expected %d statements, got %d", len(want), len(got))
+				}
+				for i := range want {
+					if got[i] != want[i] {
+						t.Errorf("Statement %d differs\nfull:        %+v\nincremental: %+v", i, want[i], got[i])
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestSplitter_Update_KeepsIdentity(t *testing.T) {
+	s := NewSplitter(WithMode(ModeHybrid))
+	prev, err := s.SplitResult(incrementalScript)
+	if err != nil {
+		t.Fatalf("SplitResult failed: %v", err)
+	}
+	before := append([]*Statement(nil), prev.Statements...)
+
+	updated, err := s.Update(prev, edit(t, prev.Text, "SET emp_name = emp_name", "SET emp_name = UPPER(emp_name)"))
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if len(updated.Statements) != len(before) {
+		t.Fatalf("Expected %d statements, got %d", len(before), len(updated.Statements))
+	}
+
+	for i, stmt := range updated.Statements {
+		changed := strings.Contains(stmt.Content, "UPPER")
+		if !changed && stmt != before[i] {
+			t.Errorf("Statement %d was not touched by the edit but lost its identity", i)
+		}
+		if stmt.Content != updated.Text[stmt.StartOffset:stmt.EndOffset] {
+			t.Errorf("Statement %d offsets do not match its content", i)
+		}
+	}
+}
+
+func TestSplitter_Update_InvalidEdit(t *testing.T) {
+	s := NewSplitter()
+	prev, err := s.SplitResult("COMMIT;")
+	if err != nil {
+		t.Fatalf("SplitResult failed: %v", err)
+	}
+	if _, err := s.Update(prev, Edit{Start: 3, End: 20}); err == nil {
+		t.Error("Expected an error for an edit past the end of the script")
+	}
+}
+
+func TestSplitter_Update_RecoversFromSyntaxError(t *testing.T) {
+	s := NewSplitter()
+	prev, err := s.SplitResult("SELECT * FROM employees;\nCOMMIT;")
+	if err != nil {
+		t.Fatalf("SplitResult failed: %v", err)
+	}
+
+	broken, err := s.Update(prev, edit(t, prev.Text, "FROM employees", "FROM"))
+	if err == nil {
+		t.Fatal("Expected a syntax error")
+	}
+	if len(broken.Statements) != 0 || broken.Text != "SELECT * FROM;\nCOMMIT;" {
+		t.Fatalf("Expected the edited text without statements, got %+v", broken)
+	}
+
+	fixed, err := s.Update(broken, edit(t, broken.Text, "FROM", "FROM dual"))
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if len(fixed.Statements) != 2 || fixed.Statements[0].Content != "SELECT * FROM dual" {
+		t.Errorf("Expected the script to be split again, got %+v", values(fixed))
+	}
+}
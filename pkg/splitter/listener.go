@@ -0,0 +1,89 @@
+package splitter
+
+import (
+	"github.com/antlr4-go/antlr/v4"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+)
+
+// Listener receives parse tree events while the splitter walks the script.
+// Listeners run in the same pass that finds statement boundaries, so they add
+// no extra parsing work.
+//
+// statementIndex is the index, in the returned slice, of the statement the
+// node belongs to, or -1 for nodes outside any statement (such as SQL*Plus
+// commands and separators).
+type Listener interface {
+	// Enter is called for every rule and token node in depth-first order
+	Enter(node ast.Node, statementIndex int)
+	// Exit is called after all children of a node have been visited
+	Exit(node ast.Node, statementIndex int)
+}
+
+// BaseListener is a Listener that ignores every event. Embed it to implement
+// only the callbacks you need.
+type BaseListener struct{}
+
+// Enter does nothing
+func (BaseListener) Enter(node ast.Node, statementIndex int) {}
+
+// Exit does nothing
+func (BaseListener) Exit(node ast.Node, statementIndex int) {}
+
+// WithListener registers a listener that is invoked during parsing. It can be
+// used several times to register multiple listeners.
+func WithListener(listener Listener) Option {
+	return func(s *Splitter) {
+		if listener != nil {
+			s.listeners = append(s.listeners, listener)
+		}
+	}
+}
+
+// listenerAdapter bridges a public Listener to the ANTLR parse tree walk
+type listenerAdapter struct {
+	listener       Listener
+	tree           *ast.Tree
+	statementIndex int
+}
+
+// newListenerAdapters wraps the given listeners for a script
+func newListenerAdapters(listeners []Listener, tree *ast.Tree) []antlr.ParseTreeListener {
+	adapters := make([]antlr.ParseTreeListener, 0, len(listeners))
+	for _, listener := range listeners {
+		adapters = append(adapters, &listenerAdapter{
+			listener:       listener,
+			tree:           tree,
+			statementIndex: -1,
+		})
+	}
+	return adapters
+}
+
+// SetStatementIndex implements internalParser.StatementAware
+func (a *listenerAdapter) SetStatementIndex(index int) {
+	a.statementIndex = index
+}
+
+// VisitTerminal reports a token node
+func (a *listenerAdapter) VisitTerminal(node antlr.TerminalNode) {
+	n := a.tree.ScriptNode(node)
+	a.listener.Enter(n, a.statementIndex)
+	a.listener.Exit(n, a.statementIndex)
+}
+
+// VisitErrorNode reports a token the parser could not match
+func (a *listenerAdapter) VisitErrorNode(node antlr.ErrorNode) {
+	n := a.tree.ScriptNode(node)
+	a.listener.Enter(n, a.statementIndex)
+	a.listener.Exit(n, a.statementIndex)
+}
+
+// EnterEveryRule reports entering a rule node
+func (a *listenerAdapter) EnterEveryRule(ctx antlr.ParserRuleContext) {
+	a.listener.Enter(a.tree.ScriptNode(ctx), a.statementIndex)
+}
+
+// ExitEveryRule reports leaving a rule node
+func (a *listenerAdapter) ExitEveryRule(ctx antlr.ParserRuleContext) {
+	a.listener.Exit(a.tree.ScriptNode(ctx), a.statementIndex)
+}
@@ -0,0 +1,67 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
+)
+
+// countingListener counts identifiers and records which statement they belong to
+type countingListener struct {
+	BaseListener
+	identifiers map[int]int
+	depth       int
+	maxDepth    int
+}
+
+func (l *countingListener) Enter(node ast.Node, statementIndex int) {
+	l.depth++
+	if l.depth > l.maxDepth {
+		l.maxDepth = l.depth
+	}
+	if node.Kind() == ast.KindToken && node.TokenName() == "REGULAR_ID" {
+		l.identifiers[statementIndex]++
+	}
+}
+
+func (l *countingListener) Exit(node ast.Node, statementIndex int) {
+	l.depth--
+}
+
+func TestSplitter_WithListener(t *testing.T) {
+	input := `SELECT emp_id, first_name FROM employees;
+UPDATE departments SET budget = 0;
+BEGIN
+    cleanup_job;
+END;
+/`
+
+	listener := &countingListener{identifiers: make(map[int]int)}
+	s := NewSplitter(WithListener(listener))
+
+	statements, err := s.SplitString(input)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(statements))
+	}
+
+	expected := map[int]int{
+		0: 3, // emp_id, first_name, employees
+		1: 2, // departments, budget
+		2: 1, // cleanup_job
+	}
+	for index, count := range expected {
+		if listener.identifiers[index] != count {
+			t.Errorf("Statement %d: expected %d identifiers, got %d", index, count, listener.identifiers[index])
+		}
+	}
+
+	if listener.depth != 0 {
+		t.Errorf("Expected balanced Enter/Exit calls, depth ended at %d", listener.depth)
+	}
+	if listener.maxDepth < 3 {
+		t.Errorf("Expected nested rule events, max depth was %d", listener.maxDepth)
+	}
+}
@@ -1,6 +1,7 @@
 package splitter
 
 import (
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
 	"github.com/zodimo/go-plsql-statement-splitter/pkg/statement"
 )
 
@@ -11,14 +12,52 @@ type Statement struct {
 	EndLine     int            `json:"endLine"`
 	StartColumn int            `json:"startColumn"`
 	EndColumn   int            `json:"endColumn"`
-	Type        statement.Type `json:"type,omitempty"` // If available from ANTLR parser
+	Type        statement.Type `json:"type,omitempty"`    // If available from ANTLR parser
+	StartOffset int            `json:"startOffset"`       // Byte offset of the statement in the script
+	EndOffset   int            `json:"endOffset"`         // Byte offset just past the end of the statement
+	Parsed      bool           `json:"parsed"`            // False when the boundaries come from the token stream only
+	Wrapped     bool           `json:"wrapped,omitempty"` // True for units obfuscated with the wrap utility, whose body is opaque
+
+	node ast.Node
+}
+
+// Node returns the parse tree of the statement. It is nil unless the splitter
+// was created with WithParseTree(true).
+func (s Statement) Node() ast.Node {
+	return s.node
+}
+
+// Bytes returns the statement text from the script it was split from, using
+// its byte offsets. It is useful with WithContent(false), which leaves Content
+// empty so that statements do not keep the script alive.
+func (s Statement) Bytes(script []byte) []byte {
+	if s.EndOffset > len(script) || s.StartOffset > s.EndOffset {
+		return nil
+	}
+	return script[s.StartOffset:s.EndOffset]
 }
 
 // SyntaxError represents a syntax error in a PL/SQL script
 type SyntaxError struct {
-	Line      int    `json:"line"`      // Line number where the error occurred
-	Column    int    `json:"column"`    // Column number where the error occurred
-	Message   string `json:"message"`   // Error message
-	Statement string `json:"statement"` // The statement that caused the error
-	Context   string `json:"context"`   // Context lines showing the error in context
+	Line           int            `json:"line"`                    // Line number where the error occurred
+	Column         int            `json:"column"`                  // Column number where the error occurred
+	Message        string         `json:"message"`                 // Error message
+	Kind           ErrorKind      `json:"kind"`                    // Whether the lexer or the parser found the error
+	Code           string         `json:"code,omitempty"`          // Code of a recognized error, such as CodeMissingEnd
+	ParserMessage  string         `json:"parserMessage,omitempty"` // Parser's own message, when Message explains a recognized error
+	Statement      string         `json:"statement"`               // The statement that caused the error
+	Context        string         `json:"context"`                 // Context lines showing the error in context
+	Token          *ErrorToken    `json:"token,omitempty"`         // Offending token, nil when the error is not on a token
+	Expected       []string       `json:"expected,omitempty"`      // Tokens the parser expected, such as END, ; or REGULAR_ID
+	StatementIndex int            `json:"statementIndex"`          // Index of the enclosing statement in the script, -1 when unknown
+	StatementType  statement.Type `json:"statementType,omitempty"` // Type of the enclosing statement
+	File           string         `json:"file,omitempty"`          // File the script was read from, if any
+}
+
+// ErrorToken is the token at which a syntax error was found
+type ErrorToken struct {
+	Text        string `json:"text"`
+	Type        string `json:"type"`        // Symbolic name of the token type, such as REGULAR_ID or EOF, empty for lexical errors
+	StartOffset int    `json:"startOffset"` // Byte offset of the token in the script
+	EndOffset   int    `json:"endOffset"`   // Byte offset just past the token
 }
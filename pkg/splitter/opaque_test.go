@@ -0,0 +1,42 @@
+package splitter
+
+import (
+	"testing"
+
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/statement"
+)
+
+func TestSplitter_OpaqueUnits(t *testing.T) {
+	input := `CREATE OR REPLACE PROCEDURE touch wrapped
+a000000
+1
+abcd
+8 2d
+Xy1+aQ/WXq0;'x
+/
+CREATE OR REPLACE AND RESOLVE JAVA SOURCE NAMED "Util" AS
+public class Util { public static int one() { return 1; } }
+/
+BEGIN touch; END;
+/`
+
+	for _, mode := range []Mode{ModeParser, ModeLexical, ModeHybrid} {
+		statements, err := NewSplitter(WithMode(mode)).SplitString(input)
+		if err != nil {
+			t.Fatalf("Mode %v: SplitString failed: %v", mode, err)
+		}
+		if len(statements) != 3 {
+			t.Fatalf("Mode %v: expected 3 statements, got %d", mode, len(statements))
+		}
+
+		if statements[0].Type != statement.TypeCreateProcedure || !statements[0].Wrapped {
+			t.Errorf("Mode %v: expected a wrapped procedure, got %s wrapped=%t", mode, statements[0].Type, statements[0].Wrapped)
+		}
+		if statements[1].Type != statement.TypeCreateJavaSource {
+			t.Errorf("Mode %v: expected a Java source, got %s", mode, statements[1].Type)
+		}
+		if statements[2].Type != statement.TypePlsqlBlock || statements[2].Wrapped {
+			t.Errorf("Mode %v: expected a plain block, got %s wrapped=%t", mode, statements[2].Type, statements[2].Wrapped)
+		}
+	}
+}
@@ -6,12 +6,21 @@ import (
 	"io"
 	"os"
 	"strings"
+	"unsafe"
 
 	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+	"github.com/zodimo/go-plsql-statement-splitter/pkg/ast"
 	"github.com/zodimo/go-plsql-statement-splitter/pkg/statement"
 )
 
-// Splitter is responsible for splitting PL/SQL scripts into individual statements
+// Splitter is responsible for splitting PL/SQL scripts into individual statements.
+//
+// A Splitter is safe for concurrent use by multiple goroutines: its
+// configuration is never modified after NewSplitter returns, and each call
+// borrows its own lexer and parser from a process-wide pool. The ANTLR DFA and
+// prediction context caches are shared by all Splitters and grow as scripts
+// are parsed. Listeners registered with WithListener are shared as well, so
+// they must be safe for concurrent use if the Splitter is.
 type Splitter struct {
 	// Configuration options
 	includePosition       bool
@@ -20,6 +29,19 @@ type Splitter struct {
 	includeContext        bool
 	includeErrorStatement bool
 	contextLines          int // Number of context lines to show before and after the error
+	includeParseTree      bool
+	listeners             []Listener
+	mode                  Mode
+	warmup                bool
+	concurrency           int  // Number of files split in parallel by SplitFiles and SplitFS
+	omitContent           bool // Leave Statement.Content empty; statements only carry offsets
+	stopOnError           bool // Stop scheduling files after the first failure
+	cache                 *resultCache
+	oracleVersion         OracleVersion
+	ccFlags               map[string]any             // Conditional compilation values; nil parses first branches
+	terminators           internalParser.Terminators // SQL*Plus terminator settings at the start of a script
+	warningsAsErrors      map[string]bool            // Codes of the warnings promoted to errors, empty for all, nil for none
+	blockChecks           bool                       // Report block structure errors as SemanticError errors
 }
 
 // NewSplitter creates a new Splitter instance with the provided options
@@ -38,12 +60,32 @@ func NewSplitter(options ...Option) *Splitter {
 		option(s)
 	}
 
+	if s.warmup {
+		internalParser.WarmUp()
+	}
+
 	return s
 }
 
 // Option represents a configuration option for the Splitter
 type Option func(*Splitter)
 
+// Mode selects how the Splitter finds statement boundaries
+type Mode int
+
+const (
+	// ModeParser runs the full PL/SQL parser and reports syntax errors (default)
+	ModeParser Mode = iota
+	// ModeLexical finds boundaries from the token stream alone. It is much
+	// faster on large, trusted scripts but does not detect syntax errors, build
+	// parse trees or invoke listeners.
+	ModeLexical
+	// ModeHybrid runs the full parser but does not fail on syntax errors.
+	// Statements in regions the grammar cannot parse are split on their ; and /
+	// boundaries instead, with Type UNKNOWN and Parsed set to false.
+	ModeHybrid
+)
+
 // WithPositionInfo configures whether position information is included
 func WithPositionInfo(include bool) Option {
 	return func(s *Splitter) {
@@ -88,6 +130,45 @@ func WithErrorContextLines(lines int) Option {
 	}
 }
 
+// WithParseTree configures whether each statement keeps its parse tree, available through Statement.Node
+func WithParseTree(include bool) Option {
+	return func(s *Splitter) {
+		s.includeParseTree = include
+	}
+}
+
+// WithMode configures how statement boundaries are found
+func WithMode(mode Mode) Option {
+	return func(s *Splitter) {
+		s.mode = mode
+	}
+}
+
+// WithContent configures whether Statement.Content is filled. Content shares
+// memory with the script, so retained statements keep the whole script alive;
+// with WithContent(false) statements only carry StartOffset and EndOffset,
+// and Statement.Bytes recovers the text from the script.
+func WithContent(include bool) Option {
+	return func(s *Splitter) {
+		s.omitContent = !include
+	}
+}
+
+// WithWarmup configures whether NewSplitter populates the shared parser caches
+// by parsing a representative script, so that the first real call is not slow.
+// The warm-up runs at most once per process.
+func WithWarmup(warmup bool) Option {
+	return func(s *Splitter) {
+		s.warmup = warmup
+	}
+}
+
+// SplitBytes splits a PL/SQL script held in a byte slice into individual statements
+func SplitBytes(data []byte) ([]Statement, error) {
+	splitter := NewSplitter()
+	return splitter.SplitBytes(data)
+}
+
 // SplitFile splits a PL/SQL script file into individual statements
 func SplitFile(filePath string) ([]Statement, error) {
 	splitter := NewSplitter()
@@ -110,10 +191,11 @@ func SplitString(content string) ([]Statement, error) {
 func (s *Splitter) SplitFile(filePath string) ([]Statement, error) {
 	data, err := os.ReadFile(filePath)
 	if err != nil {
-		return nil, fmt.Errorf("error reading file: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrReadFile, err)
 	}
 
-	return s.SplitString(string(data))
+	statements, err := s.SplitBytes(data)
+	return statements, withFile(err, filePath)
 }
 
 // SplitReader splits a PL/SQL script from an io.Reader into individual statements
@@ -123,80 +205,89 @@ func (s *Splitter) SplitReader(reader io.Reader) ([]Statement, error) {
 		return nil, fmt.Errorf("error reading input: %w", err)
 	}
 
-	return s.SplitString(string(data))
+	return s.SplitBytes(data)
+}
+
+// SplitBytes splits a PL/SQL script held in a byte slice without copying it.
+// The contents of the returned statements share memory with data, so data
+// must not be modified while the statements are in use.
+func (s *Splitter) SplitBytes(data []byte) ([]Statement, error) {
+	return s.SplitString(unsafe.String(unsafe.SliceData(data), len(data)))
 }
 
 // SplitString splits a PL/SQL script string into individual statements
 func (s *Splitter) SplitString(content string) ([]Statement, error) {
+	statements, err := s.splitString(content)
+
+	// Warnings promoted to errors reject a script that parses
+	if err == nil && s.warningsAsErrors != nil {
+		if promoted := firstPromoted(s.Warnings(content)); promoted != nil {
+			return nil, promoted
+		}
+	}
+	return statements, err
+}
+
+// splitString splits content, through the cache when one is configured
+func (s *Splitter) splitString(content string) ([]Statement, error) {
 	if strings.TrimSpace(content) == "" {
 		return []Statement{}, nil
 	}
 
-	// Use the ANTLR4 parser to parse the SQL
-	parsedStatements, syntaxErrors, err := internalParser.ParseStringWithOptions(content, s.maxErrors, s.contextLines)
-	if err != nil {
-		return nil, fmt.Errorf("parser error: %w", err)
+	// Parse trees and listeners need a real parse
+	if s.cache == nil || s.includeParseTree || len(s.listeners) > 0 {
+		return s.split(content)
 	}
 
-	// If there are syntax errors, return an error
-	if len(syntaxErrors) > 0 {
-		if s.verboseErrors {
-			// Return all errors up to the maximum
-			var errorMessages []string
-			maxErrors := s.maxErrors
-			if maxErrors <= 0 || maxErrors > len(syntaxErrors) {
-				maxErrors = len(syntaxErrors)
-			}
-
-			for i := 0; i < maxErrors; i++ {
-				err := syntaxErrors[i]
-				errMsg := fmt.Sprintf("Line %d, Column %d: %s", err.Line, err.Column, err.Message)
-
-				// Include context if configured
-				if s.includeContext && err.Context != "" {
-					errMsg += "\n" + err.Context
-				}
-
-				errorMessages = append(errorMessages, errMsg)
-			}
-
-			if len(syntaxErrors) > maxErrors {
-				errorMessages = append(errorMessages, fmt.Sprintf("... and %d more errors", len(syntaxErrors)-maxErrors))
-			}
-
-			// Create the syntax error with detailed information
-			syntaxErr := &SyntaxError{
-				Message: strings.Join(errorMessages, "\n"),
-				Line:    syntaxErrors[0].Line,
-				Column:  syntaxErrors[0].Column,
-				Context: syntaxErrors[0].Context,
-			}
+	key := s.cacheKey(content)
+	if entry, ok := s.cache.load(key); ok {
+		if entry.Error != nil {
+			return nil, entry.Error
+		}
+		if entry.Errors != nil {
+			return nil, entry.Errors
+		}
+		return entry.Statements, nil
+	}
 
-			// Include statement content if configured and available
-			if s.includeErrorStatement && syntaxErrors[0].Context != "" {
-				syntaxErr.Statement = strings.Split(syntaxErrors[0].Context, "\n")[0]
-			}
+	statements, err := s.split(content)
+	switch e := err.(type) {
+	case *SyntaxError:
+		s.cache.store(key, cacheEntry{Error: e})
+	case SyntaxErrors:
+		s.cache.store(key, cacheEntry{Errors: e})
+	case nil:
+		s.cache.store(key, cacheEntry{Statements: statements})
+	}
+	return statements, err
+}
 
-			return nil, syntaxErr
-		} else {
-			// Just return the first error
-			firstError := syntaxErrors[0]
-
-			// Create the syntax error with basic information
-			syntaxErr := &SyntaxError{
-				Message: firstError.Message,
-				Line:    firstError.Line,
-				Column:  firstError.Column,
-				Context: firstError.Context,
-			}
+// split parses content and converts the result to the public model
+func (s *Splitter) split(content string) ([]Statement, error) {
+	// The tree is shared by statement nodes and listener events
+	var tree *ast.Tree
+	if s.mode == ModeParser && (s.includeParseTree || len(s.listeners) > 0) {
+		tree = ast.NewTree(content)
+	}
 
-			// Include statement content if configured and available
-			if s.includeErrorStatement && firstError.Context != "" {
-				syntaxErr.Statement = strings.Split(firstError.Context, "\n")[0]
-			}
+	// Use the ANTLR4 parser to parse the SQL
+	parsedStatements, syntaxErrors, err := internalParser.Parse(content, internalParser.ParseOptions{
+		MaxErrors:    s.maxErrors,
+		ContextLines: s.contextLines,
+		Listeners:    newListenerAdapters(s.listeners, tree),
+		Mode:         s.internalMode(),
+		Version:      s.oracleVersion.internal(),
+		CCFlags:      s.ccFlags,
+		Terminators:  s.terminators,
+		BlockChecks:  s.blockChecks,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrParsing, err)
+	}
 
-			return nil, syntaxErr
-		}
+	// If there are syntax errors, return an error unless they were handled by the hybrid fallback
+	if len(syntaxErrors) > 0 && s.mode != ModeHybrid {
+		return nil, s.rejection(syntaxErrors)
 	}
 
 	// Convert internal statement representation to public model
@@ -205,6 +296,16 @@ func (s *Splitter) SplitString(content string) ([]Statement, error) {
 		statement := Statement{
 			Content: stmt.Content,
 			Type:    statement.Parse(stmt.Type),
+			Parsed:  stmt.Parsed,
+			Wrapped: stmt.Wrapped,
+		}
+		if s.omitContent {
+			statement.Content = ""
+		}
+
+		// The parse tree is only retained when requested, as it keeps the whole tree in memory
+		if s.includeParseTree && stmt.Tree != nil {
+			statement.node = tree.Node(stmt.Tree)
 		}
 
 		// Include position information if configured
@@ -215,24 +316,109 @@ func (s *Splitter) SplitString(content string) ([]Statement, error) {
 			statement.EndColumn = stmt.EndColumn
 		}
 
+		// Offsets are the only way back to the text when content is omitted
+		if s.includePosition || s.omitContent {
+			statement.StartOffset = stmt.StartOffset
+			statement.EndOffset = stmt.EndOffset
+		}
+
 		statements = append(statements, statement)
 	}
 
 	return statements, nil
 }
 
-// Error messages
+// rejection returns the error rejecting a script with syntax errors: every
+// error up to the maximum with WithVerboseErrors, or the first one
+func (s *Splitter) rejection(syntaxErrors []internalParser.SyntaxError) error {
+	if s.verboseErrors {
+		// Return all errors up to the maximum
+		maxErrors := s.maxErrors
+		if maxErrors <= 0 || maxErrors > len(syntaxErrors) {
+			maxErrors = len(syntaxErrors)
+		}
+
+		// Context lines are only included when configured, as a script
+		// can have many errors
+		all := make(SyntaxErrors, 0, maxErrors)
+		for _, err := range syntaxErrors[:maxErrors] {
+			syntaxErr := s.syntaxError(err)
+			if !s.includeContext {
+				syntaxErr.Context = ""
+			}
+			all = append(all, &syntaxErr)
+		}
+		return all
+	}
+
+	// Just return the first error
+	syntaxErr := s.syntaxError(syntaxErrors[0])
+	return &syntaxErr
+}
+
+// internalMode maps the configured Mode to the internal parser mode
+func (s *Splitter) internalMode() internalParser.Mode {
+	switch s.mode {
+	case ModeLexical:
+		return internalParser.ModeLexical
+	case ModeHybrid:
+		return internalParser.ModeHybrid
+	}
+	return internalParser.ModeFull
+}
+
+// Error messages. Errors returned by the Splitter wrap one of these, so they
+// can be told apart with errors.Is.
 var (
+	// ErrEmptyInput is not returned: empty input splits into no statements.
+	//
+	// Deprecated: check for an empty result instead.
 	ErrEmptyInput = errors.New("empty input")
-	ErrSyntax     = errors.New("syntax error")
-	ErrReadFile   = errors.New("error reading file")
-	ErrParsing    = errors.New("error parsing SQL")
+	// ErrSyntax is wrapped by every *SyntaxError
+	ErrSyntax = errors.New("syntax error")
+	// ErrReadFile is wrapped by errors reading a script file
+	ErrReadFile = errors.New("error reading file")
+	// ErrParsing is wrapped by failures of the parser itself
+	ErrParsing = errors.New("error parsing SQL")
 )
 
+// SyntaxErrors are the syntax errors of a script, in the order they were
+// found. They are returned instead of a single *SyntaxError when the Splitter
+// was created with WithVerboseErrors(true). errors.As finds the first
+// *SyntaxError among them.
+type SyntaxErrors []*SyntaxError
+
+// Error returns the errors one after another
+func (e SyntaxErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// Unwrap returns the individual errors
+func (e SyntaxErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// Unwrap returns ErrSyntax
+func (e *SyntaxError) Unwrap() error {
+	return ErrSyntax
+}
+
 // Error implements the error interface for SyntaxError
 func (e *SyntaxError) Error() string {
+	message := e.Message
+	if e.Code != "" {
+		message = e.Code + ": " + message
+	}
 	if e.Context != "" {
-		return fmt.Sprintf("syntax error at line %d, column %d: %s\n%s", e.Line, e.Column, e.Message, e.Context)
+		return fmt.Sprintf("syntax error at line %d, column %d: %s\n%s", e.Line, e.Column, message, e.Context)
 	}
 
 	// Handle case where error message is too verbose (containing the full token list)
@@ -245,7 +431,7 @@ func (e *SyntaxError) Error() string {
 		}
 	}
 
-	return fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, e.Message)
+	return fmt.Sprintf("syntax error at line %d, column %d: %s", e.Line, e.Column, message)
 }
 
 // GetSyntaxErrors returns all syntax errors that were encountered during parsing
@@ -255,30 +441,69 @@ func (s *Splitter) GetSyntaxErrors(content string) ([]SyntaxError, error) {
 	}
 
 	// Use the ANTLR4 parser to parse the SQL
-	_, syntaxErrors, err := internalParser.ParseStringWithOptions(content, s.maxErrors, s.contextLines)
+	_, syntaxErrors, err := internalParser.Parse(content, internalParser.ParseOptions{
+		MaxErrors:    s.maxErrors,
+		ContextLines: s.contextLines,
+		Version:      s.oracleVersion.internal(),
+		CCFlags:      s.ccFlags,
+		Terminators:  s.terminators,
+		BlockChecks:  s.blockChecks,
+	})
 	if err != nil {
-		return nil, fmt.Errorf("parser error: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrParsing, err)
 	}
 
 	// Convert internal syntax errors to public model
 	errors := make([]SyntaxError, 0, len(syntaxErrors))
 	for _, err := range syntaxErrors {
-		syntaxErr := SyntaxError{
-			Message: err.Message,
-			Line:    err.Line,
-			Column:  err.Column,
-			Context: err.Context,
-		}
+		errors = append(errors, s.syntaxError(err))
+	}
 
-		// Include statement content if configured and available
-		if s.includeErrorStatement && err.Context != "" {
-			syntaxErr.Statement = strings.Split(err.Context, "\n")[0]
-		}
+	return errors, nil
+}
 
-		errors = append(errors, syntaxErr)
+// syntaxError converts an internal syntax error to the public model
+func (s *Splitter) syntaxError(err internalParser.SyntaxError) SyntaxError {
+	syntaxErr := SyntaxError{
+		Message:        err.Message,
+		Kind:           ErrorKind(err.Kind),
+		Code:           err.Code,
+		ParserMessage:  err.ParserMessage,
+		Line:           err.Line,
+		Column:         err.Column,
+		Context:        err.Context,
+		Expected:       err.Expected,
+		StatementIndex: err.StatementIndex,
+	}
+	if err.Token != nil {
+		token := ErrorToken(*err.Token)
+		syntaxErr.Token = &token
+	}
+	if err.StatementIndex >= 0 {
+		syntaxErr.StatementType = statement.Parse(err.StatementType)
 	}
 
-	return errors, nil
+	// Include the enclosing statement if configured
+	if s.includeErrorStatement {
+		syntaxErr.Statement = err.Statement
+	}
+
+	return syntaxErr
+}
+
+// withFile records the file a script was read from in its syntax errors
+func withFile(err error, name string) error {
+	switch e := err.(type) {
+	case *SyntaxError:
+		e.File = name
+	case SyntaxErrors:
+		for _, syntaxErr := range e {
+			syntaxErr.File = name
+		}
+	case *Diagnostic:
+		e.File = name
+	}
+	return err
 }
 
 // GetAllSyntaxErrors returns all syntax errors without limiting to maxErrors
@@ -289,7 +514,10 @@ func (s *Splitter) GetAllSyntaxErrors(content string) ([]SyntaxError, error) {
 		WithErrorStatement(s.includeErrorStatement),
 		WithErrorContext(s.includeContext),
 		WithErrorContextLines(s.contextLines),
+		WithOracleVersion(s.oracleVersion),
 	)
+	tempSplitter.ccFlags = s.ccFlags
+	tempSplitter.terminators = s.terminators
 
 	return tempSplitter.GetSyntaxErrors(content)
 }
@@ -1,8 +1,11 @@
 package splitter
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -212,14 +215,18 @@ func TestSplitter_WithVerboseErrors(t *testing.T) {
 			t.Errorf("Expected non-empty error message")
 		}
 
-		// Verbose errors should contain more detailed information
-		syntaxErr, ok := err.(*SyntaxError)
-		if !ok {
-			t.Fatalf("Expected SyntaxError, got %T", err)
+		// Verbose errors return every syntax error
+		syntaxErrs, ok := err.(SyntaxErrors)
+		if !ok || len(syntaxErrs) == 0 {
+			t.Fatalf("Expected SyntaxErrors, got %T", err)
 		}
 
-		if len(syntaxErr.Message) < 20 {
-			t.Errorf("Verbose error message too short: %s", syntaxErr.Message)
+		var syntaxErr *SyntaxError
+		if !errors.As(err, &syntaxErr) || syntaxErr != syntaxErrs[0] {
+			t.Errorf("Expected errors.As to find the first SyntaxError")
+		}
+		if !errors.Is(err, ErrSyntax) {
+			t.Errorf("Expected the errors to wrap ErrSyntax")
 		}
 	})
 
@@ -420,8 +427,8 @@ func TestSplitString_InvalidSQLSamples(t *testing.T) {
 			}
 
 			// Check that we get a SyntaxError
-			syntaxErr, ok := err.(*SyntaxError)
-			if !ok {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
 				t.Errorf("Expected SyntaxError, got %T", err)
 			} else {
 				if syntaxErr.Line == 0 || syntaxErr.Column == 0 {
@@ -664,7 +671,7 @@ func TestSplitter_WithErrorContextLines(t *testing.T) {
 			// No need to check for specific context format, as that's implementation-dependent
 			// Just make sure we get a valid error message that contains core information
 			if tc.contextLines > 0 {
-				if !strings.Contains(errMsg, "no viable alternative") && !strings.Contains(errMsg, "extraneous input") && !strings.Contains(errMsg, "missing") {
+				if !strings.Contains(errMsg, "no viable alternative") && !strings.Contains(errMsg, "extraneous input") && !strings.Contains(errMsg, "missing") && !strings.Contains(errMsg, "unterminated") {
 					t.Errorf("Error message should contain error details but doesn't: %s", errMsg)
 				}
 			}
@@ -679,3 +686,192 @@ func min(a, b int) int {
 	}
 	return b
 }
+
+func TestSplitter_WithModeLexical(t *testing.T) {
+	parserSplitter := NewSplitter()
+	lexicalSplitter := NewSplitter(WithMode(ModeLexical))
+
+	for name, sql := range samples.GetComplexSQLSamples() {
+		t.Run(name, func(t *testing.T) {
+			expected, err := parserSplitter.SplitString(sql)
+			if err != nil {
+				t.Fatalf("Parser mode failed: %v", err)
+			}
+			actual, err := lexicalSplitter.SplitString(sql)
+			if err != nil {
+				t.Fatalf("Lexical mode failed: %v", err)
+			}
+
+			if len(actual) != len(expected) {
+				t.Fatalf("Expected %d statements, got %d", len(expected), len(actual))
+			}
+			for i := range expected {
+				if actual[i].Content != expected[i].Content || actual[i].Type != expected[i].Type {
+					t.Errorf("Statement %d: expected %s %q, got %s %q", i, expected[i].Type, expected[i].Content, actual[i].Type, actual[i].Content)
+				}
+			}
+		})
+	}
+}
+
+func TestSplitter_WithModeLexical_NoSyntaxErrors(t *testing.T) {
+	// Lexical mode trusts its input, so malformed statements are still split
+	s := NewSplitter(WithMode(ModeLexical), WithParseTree(true))
+	statements, err := s.SplitString("SELECT * FROM; DELETE FROM employees;")
+	if err != nil {
+		t.Fatalf("Expected no error in lexical mode, got %v", err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+	if statements[0].Node() != nil {
+		t.Errorf("Lexical mode should not build parse trees")
+	}
+}
+
+func TestSplitter_WithModeHybrid(t *testing.T) {
+	input := `CREATE TABLE audit_log (log_id NUMBER);
+FROBNICATE audit_log;
+/
+BEGIN
+    DELETE FROM audit_log;
+END;
+/`
+
+	// The default mode fails on the unsupported statement
+	if _, err := SplitString(input); err == nil {
+		t.Fatalf("Expected a syntax error in parser mode")
+	}
+
+	statements, err := NewSplitter(WithMode(ModeHybrid)).SplitString(input)
+	if err != nil {
+		t.Fatalf("Hybrid mode failed: %v", err)
+	}
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 statements, got %d", len(statements))
+	}
+
+	unknown := statements[1]
+	if unknown.Type != statement.TypeUnknown || unknown.Parsed || unknown.Content != "FROBNICATE audit_log" {
+		t.Errorf("Expected an unparsed UNKNOWN statement, got %+v", unknown)
+	}
+	if !statements[0].Parsed || !statements[2].Parsed {
+		t.Errorf("Statements around the error should be parsed")
+	}
+}
+
+func TestSplitter_ConcurrentUse(t *testing.T) {
+	s := NewSplitter(WithWarmup(true))
+	sql := samples.GetComplexSQLSamples()["package_body"]
+
+	expected, err := s.SplitString(sql)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		go func() {
+			statements, err := s.SplitString(sql)
+			if err == nil && (len(statements) != len(expected) || statements[0].Content != expected[0].Content) {
+				err = fmt.Errorf("expected %d statements, got %d", len(expected), len(statements))
+			}
+			errs <- err
+		}()
+	}
+	for i := 0; i < 8; i++ {
+		if err := <-errs; err != nil {
+			t.Errorf("Concurrent SplitString failed: %v", err)
+		}
+	}
+}
+
+func TestSplitBytes(t *testing.T) {
+	script := []byte("INSERT INTO customers (full_name) VALUES ('Zoë');\nDELETE FROM customers WHERE full_name = 'Ünal';")
+
+	statements, err := SplitBytes(script)
+	if err != nil {
+		t.Fatalf("SplitBytes failed: %v", err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+
+	for i, stmt := range statements {
+		if got := string(stmt.Bytes(script)); got != stmt.Content {
+			t.Errorf("Statement %d: offsets give %q, content is %q", i, got, stmt.Content)
+		}
+	}
+	if statements[1].Content != "DELETE FROM customers WHERE full_name = 'Ünal'" {
+		t.Errorf("Unexpected content %q", statements[1].Content)
+	}
+}
+
+func TestSplitter_WithContent(t *testing.T) {
+	script := []byte("SELECT * FROM employees;\nCOMMIT;")
+
+	statements, err := NewSplitter(WithContent(false), WithPositionInfo(false)).SplitBytes(script)
+	if err != nil {
+		t.Fatalf("SplitBytes failed: %v", err)
+	}
+	if len(statements) != 2 {
+		t.Fatalf("Expected 2 statements, got %d", len(statements))
+	}
+
+	expected := []string{"SELECT * FROM employees", "COMMIT"}
+	for i, stmt := range statements {
+		if stmt.Content != "" {
+			t.Errorf("Statement %d: expected empty content, got %q", i, stmt.Content)
+		}
+		if got := string(stmt.Bytes(script)); got != expected[i] {
+			t.Errorf("Statement %d: expected %q, got %q", i, expected[i], got)
+		}
+	}
+}
+
+// TestSplitter_SyntaxErrorDetails tests the structured fields of a syntax error
+func TestSplitter_SyntaxErrorDetails(t *testing.T) {
+	input := "SELECT * FROM dual;\nDELETE FROM;\n"
+	path := filepath.Join(t.TempDir(), "broken.sql")
+	if err := os.WriteFile(path, []byte(input), 0644); err != nil {
+		t.Fatalf("Failed to write script: %v", err)
+	}
+
+	_, err := NewSplitter(WithErrorStatement(true)).SplitFile(path)
+	syntaxErr, ok := err.(*SyntaxError)
+	if !ok {
+		t.Fatalf("Expected SyntaxError, got %T", err)
+	}
+
+	if syntaxErr.Token == nil || syntaxErr.Token.Text != ";" || syntaxErr.Token.Type != "SEMICOLON" {
+		t.Errorf("Expected the offending ; token, got %+v", syntaxErr.Token)
+	}
+	if len(syntaxErr.Expected) == 0 {
+		t.Error("Expected the tokens the parser expected")
+	}
+	if syntaxErr.StatementIndex != 1 || syntaxErr.StatementType != statement.TypeDelete {
+		t.Errorf("Expected statement 1 of type DELETE, got %d of type %s", syntaxErr.StatementIndex, syntaxErr.StatementType)
+	}
+	if syntaxErr.Statement != "DELETE FROM" {
+		t.Errorf("Expected the enclosing statement, got %q", syntaxErr.Statement)
+	}
+	if syntaxErr.File != path {
+		t.Errorf("Expected file %s, got %s", path, syntaxErr.File)
+	}
+}
+
+// TestSplitter_ErrorSentinels tests that errors wrap the exported sentinels
+func TestSplitter_ErrorSentinels(t *testing.T) {
+	_, err := NewSplitter().SplitString("SELECT * FROM;")
+	if !errors.Is(err, ErrSyntax) {
+		t.Errorf("Expected a syntax error to wrap ErrSyntax, got %v", err)
+	}
+
+	_, err = NewSplitter().SplitFile(filepath.Join(t.TempDir(), "missing.sql"))
+	if !errors.Is(err, ErrReadFile) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected a missing file to wrap ErrReadFile and os.ErrNotExist, got %v", err)
+	}
+	if errors.Is(err, ErrSyntax) {
+		t.Errorf("Expected a read error not to be a syntax error")
+	}
+}
@@ -0,0 +1,55 @@
+package splitter
+
+import (
+	"fmt"
+
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// TerminatorOff turns a terminator off, like SET SQLTERMINATOR OFF
+const TerminatorOff = internalParser.TerminatorOff
+
+// WithSQLTerminator sets the character that ends a SQL statement when it is
+// the last character of a line, like SET SQLTERMINATOR. The default is ;,
+// which ends SQL statements anywhere. PL/SQL units are not affected, as
+// SQL*Plus only ends them at a slash or block terminator line.
+func WithSQLTerminator(c rune) Option {
+	return func(s *Splitter) {
+		if c == ';' {
+			c = 0
+		}
+		s.terminators.SQL = c
+	}
+}
+
+// WithBlockTerminator sets the character that, alone on a line, ends a
+// PL/SQL unit or SQL statement like a slash line does, as SET BLOCKTERMINATOR
+// does. The default is a period.
+func WithBlockTerminator(c rune) Option {
+	return func(s *Splitter) {
+		if c == '.' {
+			c = 0
+		}
+		s.terminators.Block = c
+	}
+}
+
+// WithSQLBlankLines configures whether blank lines are allowed inside SQL
+// statements, like SET SQLBLANKLINES. They are by default; when off, a blank
+// line ends the SQL statement before it, as it does in SQL*Plus.
+func WithSQLBlankLines(on bool) Option {
+	return func(s *Splitter) {
+		s.terminators.BlankLinesEnd = !on
+	}
+}
+
+// ParseTerminator parses a terminator written as in SET SQLTERMINATOR: ON for
+// the default, OFF for TerminatorOff, or a single character that is neither
+// alphanumeric nor whitespace
+func ParseTerminator(value string) (rune, error) {
+	c, ok := internalParser.ParseTerminator(value)
+	if !ok {
+		return 0, fmt.Errorf("invalid terminator %q, expected ON, OFF or a single symbol", value)
+	}
+	return c, nil
+}
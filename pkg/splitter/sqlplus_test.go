@@ -0,0 +1,72 @@
+package splitter
+
+import (
+	"testing"
+)
+
+func TestSplitter_Terminators(t *testing.T) {
+	input := `SELECT 1 FROM dual#
+BEGIN
+  NULL;
+END;
+!
+DELETE FROM audit_log
+
+COMMIT#
+`
+	expected := []string{"SELECT 1 FROM dual", "BEGIN\n  NULL;\nEND;", "DELETE FROM audit_log", "COMMIT"}
+
+	for _, mode := range []Mode{ModeParser, ModeLexical, ModeHybrid} {
+		s := NewSplitter(WithMode(mode), WithSQLTerminator('#'), WithBlockTerminator('!'), WithSQLBlankLines(false))
+		statements, err := s.SplitString(input)
+		if err != nil {
+			t.Fatalf("Mode %v: SplitString failed: %v", mode, err)
+		}
+		if len(statements) != len(expected) {
+			t.Fatalf("Mode %v: expected %d statements, got %d", mode, len(expected), len(statements))
+		}
+		for i, want := range expected {
+			if statements[i].Content != want {
+				t.Errorf("Mode %v: statement %d: expected %q, got %q", mode, i, want, statements[i].Content)
+			}
+		}
+	}
+}
+
+func TestSplitter_Update_SetTerminator(t *testing.T) {
+	s := NewSplitter()
+	prev, err := s.SplitResult("SELECT 1 FROM dual;\nSELECT 2 FROM dual#\nSELECT 3 FROM dual;\n")
+	if err != nil {
+		t.Fatalf("SplitResult failed: %v", err)
+	}
+
+	// The SET command changes how every later statement ends
+	updated, err := s.Update(prev, Edit{Start: 0, End: 0, Text: "SET SQLTERMINATOR #\n"})
+	if err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	expected, err := s.SplitString(updated.Text)
+	if err != nil {
+		t.Fatalf("SplitString failed: %v", err)
+	}
+	if len(updated.Statements) != len(expected) || len(expected) != 3 {
+		t.Fatalf("Expected 3 statements like a full split, got %d and %d", len(updated.Statements), len(expected))
+	}
+	for i := range expected {
+		if *updated.Statements[i] != expected[i] {
+			t.Errorf("Statement %d differs\nupdate: %+v\nsplit:  %+v", i, *updated.Statements[i], expected[i])
+		}
+	}
+}
+
+func TestParseTerminator(t *testing.T) {
+	if c, err := ParseTerminator("#"); err != nil || c != '#' {
+		t.Errorf("Expected #, got %q, %v", c, err)
+	}
+	if c, err := ParseTerminator("OFF"); err != nil || c != TerminatorOff {
+		t.Errorf("Expected TerminatorOff, got %q, %v", c, err)
+	}
+	if _, err := ParseTerminator("go"); err == nil {
+		t.Error("Expected an error for a word")
+	}
+}
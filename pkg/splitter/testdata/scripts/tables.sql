@@ -0,0 +1,10 @@
+CREATE TABLE departments (
+    dept_id NUMBER PRIMARY KEY,
+    dept_name VARCHAR2(100)
+);
+
+CREATE TABLE employees (
+    emp_id NUMBER PRIMARY KEY,
+    dept_id NUMBER REFERENCES departments (dept_id),
+    salary NUMBER(10, 2)
+);
@@ -0,0 +1,101 @@
+package splitter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// OracleVersion is an Oracle Database release whose syntax the Splitter accepts
+type OracleVersion int
+
+const (
+	// OracleDefault targets the latest supported release
+	OracleDefault OracleVersion = iota
+	Oracle10g
+	Oracle11g
+	Oracle12c
+	Oracle18c
+	Oracle19c
+	Oracle21c
+	Oracle23ai
+)
+
+// oracleVersions lists the releases in order, with their internal equivalents
+var oracleVersions = []struct {
+	version  OracleVersion
+	internal internalParser.Version
+}{
+	{Oracle10g, internalParser.Version10g},
+	{Oracle11g, internalParser.Version11g},
+	{Oracle12c, internalParser.Version12c},
+	{Oracle18c, internalParser.Version18c},
+	{Oracle19c, internalParser.Version19c},
+	{Oracle21c, internalParser.Version21c},
+	{Oracle23ai, internalParser.Version23ai},
+}
+
+// WithOracleVersion configures the Oracle release whose syntax is accepted.
+// Statements using syntax introduced after that release are reported as
+// syntax errors, as are 10g-only clauses for later releases.
+func WithOracleVersion(version OracleVersion) Option {
+	return func(s *Splitter) {
+		s.oracleVersion = version
+	}
+}
+
+// ParseOracleVersion parses a release name such as "11g", "19c" or "23ai".
+// The suffix is optional and case is ignored, so "19" and "23AI" are accepted.
+func ParseOracleVersion(name string) (OracleVersion, error) {
+	normalized := strings.ToLower(strings.TrimSpace(name))
+	for _, v := range oracleVersions {
+		release := v.version.String()
+		if normalized == release || normalized == strings.TrimRightFunc(release, unicode.IsLetter) {
+			return v.version, nil
+		}
+	}
+	return OracleDefault, fmt.Errorf("unknown Oracle version %q", name)
+}
+
+// String returns the release name, such as 19c
+func (v OracleVersion) String() string {
+	return v.internal().String()
+}
+
+// internal maps the version to the internal parser version
+func (v OracleVersion) internal() internalParser.Version {
+	for _, known := range oracleVersions {
+		if known.version == v {
+			return known.internal
+		}
+	}
+	return internalParser.VersionDefault
+}
+
+// oracleVersionOf maps an internal parser version to the release, or to
+// OracleDefault when there is none
+func oracleVersionOf(v internalParser.Version) OracleVersion {
+	for _, known := range oracleVersions {
+		if known.internal == v {
+			return known.version
+		}
+	}
+	return OracleDefault
+}
+
+// MarshalText encodes the version as its release name, such as 19c
+func (v OracleVersion) MarshalText() ([]byte, error) {
+	return []byte(v.String()), nil
+}
+
+// UnmarshalText decodes a release name accepted by ParseOracleVersion
+func (v *OracleVersion) UnmarshalText(text []byte) error {
+	version, err := ParseOracleVersion(string(text))
+	if err != nil {
+		return err
+	}
+	*v = version
+	return nil
+}
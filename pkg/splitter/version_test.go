@@ -0,0 +1,45 @@
+package splitter
+
+import "testing"
+
+func TestParseOracleVersion(t *testing.T) {
+	tests := map[string]OracleVersion{
+		"10g":  Oracle10g,
+		"11G":  Oracle11g,
+		"12":   Oracle12c,
+		"19c":  Oracle19c,
+		"23ai": Oracle23ai,
+		" 23 ": Oracle23ai,
+	}
+	for name, expected := range tests {
+		got, err := ParseOracleVersion(name)
+		if err != nil {
+			t.Errorf("ParseOracleVersion(%q) failed: %v", name, err)
+		}
+		if got != expected {
+			t.Errorf("ParseOracleVersion(%q): expected %s, got %s", name, expected, got)
+		}
+	}
+
+	if _, err := ParseOracleVersion("9i"); err == nil {
+		t.Error("Expected an error for an unsupported version")
+	}
+}
+
+func TestSplitter_WithOracleVersion(t *testing.T) {
+	input := "CREATE OR REPLACE EDITIONABLE PROCEDURE touch AS BEGIN NULL; END;"
+
+	if _, err := NewSplitter(WithOracleVersion(Oracle12c)).SplitString(input); err != nil {
+		t.Errorf("Expected 12c to accept EDITIONABLE, got %v", err)
+	}
+
+	_, err := NewSplitter(WithOracleVersion(Oracle11g)).SplitString(input)
+	if _, ok := err.(*SyntaxError); !ok {
+		t.Errorf("Expected a syntax error for 11g, got %v", err)
+	}
+
+	// The default targets the latest release
+	if _, err := NewSplitter().SplitString("SELECT * FROM employees FETCH FIRST 5 ROWS ONLY;"); err != nil {
+		t.Errorf("Expected the default version to accept FETCH FIRST, got %v", err)
+	}
+}
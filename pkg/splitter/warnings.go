@@ -0,0 +1,120 @@
+package splitter
+
+import (
+	"errors"
+	"fmt"
+
+	internalParser "github.com/zodimo/go-plsql-statement-splitter/internal/parser"
+)
+
+// Codes of the warnings about scripts that parse but are likely to run
+// differently in SQL*Plus than they read
+const (
+	CodeUnitWithoutSlash     = internalParser.CodeUnitWithoutSlash     // A PL/SQL unit is not followed by a / line
+	CodeSemicolonAfterSlash  = internalParser.CodeSemicolonAfterSlash  // A ; follows a / line
+	CodeBlankLineInStatement = internalParser.CodeBlankLineInStatement // A SQL statement holds a blank line while SQLBLANKLINES is off
+	CodeSubstitutionVariable = internalParser.CodeSubstitutionVariable // A string holds a substitution variable while DEFINE is on
+)
+
+// Severity tells whether a Diagnostic stops a script from being split
+type Severity string
+
+const (
+	SeverityWarning Severity = "warning" // Reported, but the script is split
+	SeverityError   Severity = "error"   // Promoted with WithWarningsAsErrors, the script is rejected
+)
+
+// ErrWarning is wrapped by the warnings promoted to errors with
+// WithWarningsAsErrors
+var ErrWarning = errors.New("warning treated as an error")
+
+// Diagnostic is something in a script that parses but is likely to misbehave
+// when the script is run, such as a PL/SQL unit without a / line in SQL*Plus
+// or syntax that the target release does not support
+type Diagnostic struct {
+	Code           string        `json:"code"`                 // One of the warning codes, such as CodeUnitWithoutSlash
+	Severity       Severity      `json:"severity"`             // SeverityError when promoted with WithWarningsAsErrors
+	Message        string        `json:"message"`              // Description of the likely problem
+	Line           int           `json:"line"`                 // Line of the cause
+	Column         int           `json:"column"`               // Column of the cause
+	StartOffset    int           `json:"startOffset"`          // Byte offset of the cause
+	EndOffset      int           `json:"endOffset"`            // Byte offset just past the cause
+	StatementIndex int           `json:"statementIndex"`       // Index of the enclosing statement, -1 when there is none
+	File           string        `json:"file,omitempty"`       // File the script was read from, if any
+	MinVersion     OracleVersion `json:"minVersion,omitempty"` // First release supporting the cause, for CodeUnsupportedSyntax
+}
+
+// Error implements the error interface for warnings promoted to errors
+func (d *Diagnostic) Error() string {
+	return fmt.Sprintf("%s at line %d, column %d: %s: %s", d.Severity, d.Line, d.Column, d.Code, d.Message)
+}
+
+// Unwrap returns ErrWarning for a promoted warning, so that errors.Is can
+// tell it apart from syntax errors
+func (d *Diagnostic) Unwrap() error {
+	if d.Severity == SeverityError {
+		return ErrWarning
+	}
+	return nil
+}
+
+// WithWarningsAsErrors promotes the warnings with the given codes to errors,
+// or every warning when no code is given. A script with a promoted warning is
+// rejected by the Split functions with the first one, as a *Diagnostic, and
+// the warnings returned elsewhere have SeverityError.
+func WithWarningsAsErrors(codes ...string) Option {
+	return func(s *Splitter) {
+		s.warningsAsErrors = make(map[string]bool, len(codes))
+		for _, code := range codes {
+			s.warningsAsErrors[code] = true
+		}
+	}
+}
+
+// Warnings returns the warnings about a script in script order. Statements
+// are found lexically, so warnings are found in scripts with syntax errors
+// too. SQL*Plus settings are followed through the SET SQLBLANKLINES, SET
+// DEFINE and SET SCAN commands of the script, starting from the SQL*Plus
+// defaults, and the terminators configured with WithSQLTerminator and
+// WithBlockTerminator.
+func (s *Splitter) Warnings(content string) []Diagnostic {
+	return s.diagnostics(internalParser.Warnings(content, s.terminators))
+}
+
+// diagnostics converts internal warnings to the public model
+func (s *Splitter) diagnostics(warnings []internalParser.Warning) []Diagnostic {
+	diagnostics := make([]Diagnostic, len(warnings))
+	for i, w := range warnings {
+		diagnostics[i] = Diagnostic{
+			Code:           w.Code,
+			Severity:       s.severity(w.Code),
+			Message:        w.Message,
+			Line:           w.Line,
+			Column:         w.Column,
+			StartOffset:    w.StartOffset,
+			EndOffset:      w.EndOffset,
+			StatementIndex: w.StatementIndex,
+			MinVersion:     oracleVersionOf(w.Required),
+		}
+	}
+	return diagnostics
+}
+
+// severity returns the severity of the warnings with the given code
+func (s *Splitter) severity(code string) Severity {
+	if s.warningsAsErrors != nil && (len(s.warningsAsErrors) == 0 || s.warningsAsErrors[code]) {
+		return SeverityError
+	}
+	return SeverityWarning
+}
+
+// firstPromoted returns the first of the diagnostics promoted to an error,
+// or nil
+func firstPromoted(diagnostics []Diagnostic) *Diagnostic {
+	for i := range diagnostics {
+		if diagnostics[i].Severity == SeverityError {
+			return &diagnostics[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package splitter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const unitWithoutSlash = "CREATE PROCEDURE p IS\nBEGIN\n  NULL;\nEND;\n"
+
+func TestSplitter_Warnings(t *testing.T) {
+	warnings := NewSplitter().Warnings(unitWithoutSlash + "SELECT 'AT&T' FROM dual;\n")
+	if len(warnings) != 2 {
+		t.Fatalf("Expected 2 warnings, got %+v", warnings)
+	}
+
+	if got := warnings[0]; got.Code != CodeUnitWithoutSlash || got.Severity != SeverityWarning || got.StatementIndex != 0 {
+		t.Errorf("Expected a %s warning for statement 0, got %+v", CodeUnitWithoutSlash, got)
+	}
+	if got := warnings[1]; got.Code != CodeSubstitutionVariable || got.Line != 5 || got.Column != 10 || got.StatementIndex != 1 {
+		t.Errorf("Expected a %s warning at 5:10 in statement 1, got %+v", CodeSubstitutionVariable, got)
+	}
+
+	if warnings := NewSplitter().Warnings("SET DEFINE OFF\nSELECT 'AT&T' FROM dual;\n"); len(warnings) != 0 {
+		t.Errorf("Expected no warnings with DEFINE OFF, got %+v", warnings)
+	}
+}
+
+func TestSplitter_WithWarningsAsErrors(t *testing.T) {
+	input := "SELECT 'AT&T' FROM dual;\n"
+
+	if _, err := NewSplitter().SplitString(input); err != nil {
+		t.Fatalf("Expected warnings to be ignored by default, got %v", err)
+	}
+	if _, err := NewSplitter(WithWarningsAsErrors(CodeUnitWithoutSlash)).SplitString(input); err != nil {
+		t.Fatalf("Expected other warnings to be ignored, got %v", err)
+	}
+
+	for _, s := range []*Splitter{
+		NewSplitter(WithWarningsAsErrors(CodeSubstitutionVariable)),
+		NewSplitter(WithWarningsAsErrors()),
+	} {
+		_, err := s.SplitString(input)
+		if !errors.Is(err, ErrWarning) || errors.Is(err, ErrSyntax) {
+			t.Fatalf("Expected ErrWarning, got %v", err)
+		}
+		var diagnostic *Diagnostic
+		if !errors.As(err, &diagnostic) || diagnostic.Code != CodeSubstitutionVariable || diagnostic.Severity != SeverityError {
+			t.Errorf("Expected the promoted %s warning, got %v", CodeSubstitutionVariable, err)
+		}
+	}
+}
+
+func TestSplitter_WarningsInResults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "p.sql")
+	if err := os.WriteFile(path, []byte(unitWithoutSlash), 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+
+	results, err := NewSplitter().SplitFiles(context.Background(), []string{path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	warnings := results[0].Warnings
+	if len(warnings) != 1 || warnings[0].Code != CodeUnitWithoutSlash || warnings[0].File != path {
+		t.Errorf("Expected a %s warning for %s, got %+v", CodeUnitWithoutSlash, path, warnings)
+	}
+
+	results, err = NewSplitter(WithWarningsAsErrors(CodeUnitWithoutSlash)).SplitFiles(context.Background(), []string{path})
+	if !errors.Is(err, ErrWarning) || !errors.Is(results[0].Err, ErrWarning) {
+		t.Errorf("Expected the file to be rejected, got %v", err)
+	}
+
+	result, err := NewSplitter().SplitResult(unitWithoutSlash)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(result.Warnings) != 1 || result.Warnings[0].Code != CodeUnitWithoutSlash {
+		t.Errorf("Expected a %s warning on the result, got %+v", CodeUnitWithoutSlash, result.Warnings)
+	}
+}
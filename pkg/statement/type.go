@@ -30,6 +30,7 @@ const (
 	TypeCreateMaterializedView Type = "CREATE_MATERIALIZED_VIEW"
 	TypeCreateSynonym          Type = "CREATE_SYNONYM"
 	TypeCreateDatabaseLink     Type = "CREATE_DATABASE_LINK"
+	TypeCreateJavaSource       Type = "CREATE_JAVA_SOURCE"
 	TypeCreate                 Type = "CREATE"
 	TypeAlterTable             Type = "ALTER_TABLE"
 	TypeAlterIndex             Type = "ALTER_INDEX"
@@ -127,6 +128,8 @@ func Parse(s string) Type {
 		return TypeCreateSynonym
 	case "CREATE_DATABASE_LINK":
 		return TypeCreateDatabaseLink
+	case "CREATE_JAVA_SOURCE":
+		return TypeCreateJavaSource
 	case "CREATE":
 		return TypeCreate
 	case "ALTER_TABLE":
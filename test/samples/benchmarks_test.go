@@ -24,3 +24,10 @@ func BenchmarkGetInvalidSQLSamples(b *testing.B) {
 		GetInvalidSQLSamples()
 	}
 }
+
+// BenchmarkGenerateLargeScript benchmarks the GenerateLargeScript function
+func BenchmarkGenerateLargeScript(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		GenerateLargeScript(1 << 20)
+	}
+}
@@ -1,5 +1,10 @@
 package samples
 
+import (
+	"fmt"
+	"strings"
+)
+
 // GetSimpleSQLSamples returns simple SQL samples for testing
 func GetSimpleSQLSamples() map[string]string {
 	return map[string]string{
@@ -125,6 +130,77 @@ func GetComplexSQLSamples() map[string]string {
 	}
 }
 
+// GetOracle23aiSQLSamples returns samples of syntax added in Oracle 23ai
+func GetOracle23aiSQLSamples() map[string]string {
+	return map[string]string{
+		"if_not_exists": `
+			CREATE TABLE IF NOT EXISTS employees (emp_id NUMBER, emp_name VARCHAR2(100));
+			CREATE SEQUENCE IF NOT EXISTS employees_seq;
+			CREATE INDEX IF NOT EXISTS employees_name_idx ON employees (emp_name);
+		`,
+
+		"if_exists": `
+			DROP INDEX IF EXISTS employees_name_idx;
+			DROP SEQUENCE IF EXISTS employees_seq;
+			DROP TABLE IF EXISTS employees PURGE;
+			DROP PROCEDURE IF EXISTS touch_employees;
+		`,
+
+		"boolean_column": `
+			CREATE TABLE flags (flag_id NUMBER, enabled BOOLEAN DEFAULT TRUE);
+			SELECT flag_id FROM flags WHERE enabled;
+		`,
+
+		"select_without_from": `
+			SELECT SYSDATE;
+			SELECT 1 + 1 AS two, 'x' AS letter;
+		`,
+
+		"table_value_constructor": `
+			INSERT INTO employees (emp_id, emp_name) VALUES (1, 'Ann'), (2, 'Bob'), (3, 'Cy');
+			SELECT * FROM (VALUES (1, 'Ann'), (2, 'Bob')) t (emp_id, emp_name);
+		`,
+
+		"group_by_alias": `
+			SELECT EXTRACT(YEAR FROM hire_date) AS hire_year, COUNT(*)
+			FROM employees
+			GROUP BY hire_year
+			HAVING COUNT(*) > 1;
+		`,
+
+		"domain": `
+			CREATE DOMAIN IF NOT EXISTS email_d AS VARCHAR2(320)
+				CONSTRAINT email_chk CHECK (REGEXP_LIKE(email_d, '^.+@.+$'))
+				DISPLAY LOWER(email_d)
+				ANNOTATIONS (Description 'An e-mail address');
+			CREATE TABLE contacts (contact_id NUMBER, email VARCHAR2(320) DOMAIN email_d);
+			DROP DOMAIN IF EXISTS email_d FORCE;
+		`,
+
+		"annotations": `
+			CREATE TABLE projects (
+				project_id NUMBER ANNOTATIONS (Surrogate, Display 'Project ID'),
+				project_name VARCHAR2(100)
+			) ANNOTATIONS (Owner 'PMO');
+			CREATE VIEW active_projects ANNOTATIONS (Hidden) AS SELECT * FROM projects;
+		`,
+
+		"json_duality_view": `
+			CREATE JSON RELATIONAL DUALITY VIEW department_dv AS
+			SELECT JSON {
+				'_id' : d.department_id,
+				'name' : d.department_name WITH UPDATE,
+				'employees' : [
+					SELECT JSON {'id' : e.emp_id, 'name' : e.emp_name}
+					FROM employees e WITH INSERT UPDATE DELETE
+					WHERE e.department_id = d.department_id
+				]
+			}
+			FROM departments d WITH INSERT UPDATE DELETE;
+		`,
+	}
+}
+
 // GetInvalidSQLSamples returns invalid SQL samples for testing error handling
 func GetInvalidSQLSamples() map[string]string {
 	return map[string]string{
@@ -147,3 +223,65 @@ func GetInvalidSQLSamples() map[string]string {
 		`,
 	}
 }
+
+// GenerateLargeScript returns a deployment-style script of at least minBytes
+// bytes, built by repeating a mix of DDL, DML, package and anonymous block
+// units with unique names. It is intended for benchmarks.
+func GenerateLargeScript(minBytes int) string {
+	var b strings.Builder
+	b.Grow(minBytes + 4096)
+
+	for i := 0; b.Len() < minBytes; i++ {
+		fmt.Fprintf(&b, `CREATE TABLE orders_%[1]d (
+    order_id NUMBER GENERATED ALWAYS AS IDENTITY,
+    customer_id NUMBER NOT NULL,
+    amount NUMBER(12, 2),
+    created_at DATE DEFAULT SYSDATE
+);
+
+INSERT INTO orders_%[1]d (customer_id, amount) VALUES (%[1]d, 100.50);
+INSERT INTO orders_%[1]d (customer_id, amount) VALUES (%[1]d, 250.00);
+UPDATE orders_%[1]d SET amount = amount * 1.1 WHERE customer_id = %[1]d;
+
+CREATE OR REPLACE PACKAGE order_pkg_%[1]d IS
+    FUNCTION total_for(p_customer_id IN NUMBER) RETURN NUMBER;
+    PROCEDURE purge_before(p_cutoff IN DATE);
+END order_pkg_%[1]d;
+/
+
+CREATE OR REPLACE PACKAGE BODY order_pkg_%[1]d IS
+    FUNCTION total_for(p_customer_id IN NUMBER) RETURN NUMBER IS
+        v_total NUMBER := 0;
+    BEGIN
+        SELECT NVL(SUM(amount), 0) INTO v_total
+        FROM orders_%[1]d
+        WHERE customer_id = p_customer_id;
+
+        RETURN CASE WHEN v_total > 1000 THEN v_total * 0.95 ELSE v_total END;
+    EXCEPTION
+        WHEN NO_DATA_FOUND THEN
+            RETURN 0;
+    END total_for;
+
+    PROCEDURE purge_before(p_cutoff IN DATE) IS
+    BEGIN
+        FOR r IN (SELECT order_id FROM orders_%[1]d WHERE created_at < p_cutoff) LOOP
+            DELETE FROM orders_%[1]d WHERE order_id = r.order_id;
+        END LOOP;
+        COMMIT;
+    END purge_before;
+END order_pkg_%[1]d;
+/
+
+BEGIN
+    IF order_pkg_%[1]d.total_for(%[1]d) > 0 THEN
+        order_pkg_%[1]d.purge_before(SYSDATE - 365);
+    END IF;
+END;
+/
+
+`, i)
+	}
+
+	return b.String()
+}